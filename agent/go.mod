@@ -5,16 +5,20 @@ go 1.24.4
 require (
 	github.com/alecthomas/kong v1.12.0
 	github.com/microwatcher/shared v0.0.0-00010101000000-000000000000
+	github.com/prometheus/client_model v0.6.2
+	github.com/prometheus/common v0.65.0
 	github.com/shirou/gopsutil v3.21.11+incompatible
 	google.golang.org/grpc v1.73.0
 	google.golang.org/protobuf v1.36.6
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 replace github.com/microwatcher/shared => ../shared
 
 require (
 	github.com/go-ole/go-ole v1.2.6 // indirect
-	github.com/stretchr/testify v1.10.0 // indirect
+	github.com/kr/pretty v0.3.1 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/tklauser/go-sysconf v0.3.15 // indirect
 	github.com/tklauser/numcpus v0.10.0 // indirect
 	github.com/yusufpapurcu/wmi v1.2.4 // indirect
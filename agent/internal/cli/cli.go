@@ -1,19 +1,55 @@
 package cli
 
+// Settings that can also come from the config file have no kong defaults,
+// an unset flag is what lets the file value through. Defaults live in the
+// config package.
+
+type Ingest struct {
+	IngestAddr     []string `help:"Ingest server address, repeat it to fail over between servers, defaults to localhost:50051"`
+	IngestStrategy string   `help:"How calls are spread across ingest servers (failover, round-robin), defaults to failover"`
+	TLS            *bool    `help:"Connect to ingest over TLS, implied by any of the other tls flags"`
+	TLSCA          string   `help:"CA bundle used to verify ingest, defaults to the system roots" type:"existingfile"`
+	TLSCert        string   `help:"Client certificate presented to ingest" type:"existingfile"`
+	TLSKey         string   `help:"Key of the client certificate" type:"existingfile"`
+	TLSServerName  string   `help:"Server name used to verify the ingest certificate, defaults to the host of the address"`
+}
+
+type Retry struct {
+	RetryMaxAttempts *int     `help:"Attempts per call to ingest, including the first one, defaults to 4"`
+	RetryBackoffBase string   `help:"Delay before the first retry, doubled on every retry, defaults to 250ms"`
+	RetryBackoffCap  string   `help:"Upper bound for the delay between retries, defaults to 10s"`
+	RetryJitter      *float64 `help:"Fraction of the retry delay that is randomized, between 0 and 1, defaults to 0.5"`
+	RetryCodes       []string `help:"gRPC codes that are retried, defaults to Unavailable,DeadlineExceeded,ResourceExhausted,Aborted"`
+	BreakerThreshold *int     `help:"Consecutive failed calls before pausing calls to ingest, 0 disables it, defaults to 5"`
+	BreakerCooldown  string   `help:"How long calls to ingest are paused once the breaker opens, defaults to 30s"`
+}
+
 type Start struct {
-	MetricInterval      string `help:"Interval between runs" default:"5s"`
-	HealthCheckInterval string `help:"Interval between health checks" default:"5s"`
+	MetricInterval      string `help:"Interval between runs, defaults to 5s"`
+	HealthCheckInterval string `help:"Interval between health checks, defaults to 5s"`
 	Identifier          string `help:"Identifier used to identify this device, defaults to hostname" default:""`
 	ClientID            string `help:"Client ID used to sign telemetry" default:""`
 	ClientSecret        string `help:"Secret used to sign telemetry" default:""`
+	OutboxDir           string `help:"Directory used to buffer telemetry while ingest is unreachable, defaults to the user cache directory" default:""`
+	OutboxSegmentSize   *int64 `help:"Size in MB after which a new outbox segment is started, defaults to 8"`
+	OutboxSegmentAge    string `help:"Age after which a new outbox segment is started, defaults to 1h"`
+	OutboxMaxSize       *int64 `help:"Size in MB after which the oldest buffered telemetry is dropped, defaults to 256"`
+	OutboxMaxAge        string `help:"Age after which buffered telemetry is dropped, defaults to 72h"`
+	PerCoreCPU          *bool  `help:"Report cpu utilisation per core on top of the whole host" name:"per-core-cpu"`
+	InContainer         *bool  `help:"Report the memory and cpu limits of the agent's cgroup instead of the host totals"`
+	Ingest              Ingest `embed:""`
+	Retry               Retry  `embed:""`
 }
 
 type Check struct {
 	ClientID     string `help:"Client ID used to sign telemetry" default:""`
 	ClientSecret string `help:"Secret used to sign telemetry" default:""`
+	Ingest       Ingest `embed:""`
+	Retry        Retry  `embed:""`
 }
 
 type CLI struct {
-	Start Start `cmd:"" help:"Start agent"`
-	Check Check `cmd:"" help:"Check agent connection"`
+	Config string `help:"Path to a YAML config file, flags and env vars take precedence over it" short:"c" type:"path"`
+	Start  Start  `cmd:"" help:"Start agent"`
+	Check  Check  `cmd:"" help:"Check agent connection"`
 }
@@ -1,71 +1,249 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/microwatcher/agent/internal/cli"
+	"github.com/microwatcher/agent/internal/metrics"
+	"github.com/microwatcher/agent/internal/outbox"
+	"github.com/microwatcher/agent/internal/retry"
+	"github.com/microwatcher/agent/internal/systeminformation"
 )
 
 const MinInterval = time.Second * 5
 
+// MaxCertificatesInterval keeps two scans within the two days the server
+// lists certificates from, so one missed scan doesn't hide them.
+const MaxCertificatesInterval = 24 * time.Hour
+
+const megabyte = 1 << 20
+
+const (
+	DefaultMetricInterval      = "5s"
+	DefaultHealthCheckInterval = "5s"
+	DefaultIngestAddr          = "localhost:50051"
+	DefaultOutboxSegmentSize   = 8
+	DefaultOutboxSegmentAge    = "1h"
+	DefaultOutboxMaxSize       = 256
+	DefaultOutboxMaxAge        = "72h"
+	DefaultRetryMaxAttempts    = 4
+	DefaultRetryBackoffBase    = "250ms"
+	DefaultRetryBackoffCap     = "10s"
+	DefaultRetryJitter         = 0.5
+	DefaultBreakerThreshold    = 5
+	DefaultBreakerCooldown     = "30s"
+	DefaultProcessTop          = 10
+	// certificates barely change between two scans
+	DefaultCertificatesInterval = "1h"
+	// below MinInterval so a slow collector doesn't overlap the next tick
+	DefaultCollectorTimeout = "4s"
+)
+
+var DefaultRetryCodes = []string{"Unavailable", "DeadlineExceeded", "ResourceExhausted", "Aborted"}
+
+const (
+	StrategyFailover   = "failover"
+	StrategyRoundRobin = "round-robin"
+)
+
+// KnownCollectors lists the collectors that can be toggled, all of them but
+// the OptInCollectors are enabled by default.
+var KnownCollectors = []string{"cpu", "memory", "disk", "network", "system", "process", "sensors", "cgroup", "systemd", "certificates"}
+
+var OptInCollectors = []string{"process", "cgroup", "systemd", "certificates"}
+
+type TLS struct {
+	Enabled    bool
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+type CollectorSettings struct {
+	Enabled  bool
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+type ExecCollector struct {
+	CollectorSettings
+	Name    string
+	Command []string
+	Format  string
+}
+
+type ScrapeCollector struct {
+	CollectorSettings
+	Name    string
+	URL     string
+	Filter  metrics.Filter
+	Relabel []metrics.RelabelRule
+}
+
+type LogCollector struct {
+	CollectorSettings
+	Name          string
+	Paths         []string
+	Parser        systeminformation.LogParser
+	FromBeginning bool
+}
+
+type ProbeCollector struct {
+	CollectorSettings
+	Name       string
+	Kind       string
+	Target     string
+	Assertions systeminformation.ProbeAssertions
+}
+
 type Config struct {
-	Logger              *slog.Logger
-	MetricInterval      time.Duration
-	HealthCheckInterval time.Duration
-	Identifier          string
-	ClientID            string
-	ClientSecret        []byte
+	Logger               *slog.Logger
+	MetricInterval       time.Duration
+	HealthCheckInterval  time.Duration
+	Identifier           string
+	ClientID             string
+	ClientSecret         []byte
+	IngestAddrs          []string
+	IngestStrategy       string
+	TLS                  TLS
+	OutboxDir            string
+	Outbox               outbox.Options
+	Retry                retry.Policy
+	BreakerThreshold     int
+	BreakerCooldown      time.Duration
+	Collectors           map[string]CollectorSettings
+	PerCoreCPU           bool
+	DiskFilter           systeminformation.DiskFilter
+	ProcessTop           int
+	InContainer          bool
+	ExecCollectors       []ExecCollector
+	ScrapeCollectors     []ScrapeCollector
+	LogCollectors        []LogCollector
+	ProbeCollectors      []ProbeCollector
+	SystemdUnits         []string
+	JournalPriority      string
+	CertificatePaths     []string
+	CertificateEndpoints []string
+
+	errs []error
 }
 
 func NewConfig(logger *slog.Logger) *Config {
 	return &Config{Logger: logger}
 }
 
-func (cfg *Config) SetMetricInterval(val string) *Config {
-	fromString, err := time.ParseDuration(val)
+// LoadStart builds the config for the start command, flags take precedence
+// over env vars, which take precedence over the config file.
+func LoadStart(logger *slog.Logger, path string, cliArgs cli.Start) (*Config, error) {
+	file, err := LoadFile(path)
 	if err != nil {
-		cfg.Logger.Error("failed to parse duration",
-			slog.String("value", val),
-			slog.Any("error", err),
-		)
-		os.Exit(1)
+		return nil, err
 	}
 
-	if fromString < MinInterval {
-		cfg.Logger.Warn(
-			"duration is too low",
-			slog.String("value", val),
-			slog.String("default value", MinInterval.String()),
-		)
-		fromString = MinInterval
+	cfg := NewConfig(logger).ApplyStartOverrides(cliArgs, file)
+	if err := cfg.Err(); err != nil {
+		return nil, err
 	}
 
-	cfg.MetricInterval = fromString
-	return cfg
+	return cfg, nil
 }
 
-func (cfg *Config) SetHealthCheckInterval(val string) *Config {
+// LoadCheck builds the config for the check command, see LoadStart.
+func LoadCheck(logger *slog.Logger, path string, cliArgs cli.Check) (*Config, error) {
+	file, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := NewConfig(logger).ApplyCheckOverrides(cliArgs, file)
+	if err := cfg.Err(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Err returns every validation error found while applying the settings.
+func (cfg *Config) Err() error {
+	return errors.Join(cfg.errs...)
+}
+
+func (cfg *Config) fail(err error) {
+	cfg.errs = append(cfg.errs, err)
+}
+
+// firstSet returns the first value that was set, in order of precedence.
+func firstSet[T comparable](vals ...T) T {
+	var zero T
+	for _, val := range vals {
+		if val != zero {
+			return val
+		}
+	}
+	return zero
+}
+
+func firstNonEmpty[T any](vals ...[]T) []T {
+	for _, val := range vals {
+		if len(val) > 0 {
+			return val
+		}
+	}
+	return nil
+}
+
+func ptr[T any](val T) *T {
+	return &val
+}
+
+func (cfg *Config) parseDuration(name string, val string) time.Duration {
 	fromString, err := time.ParseDuration(val)
 	if err != nil {
-		cfg.Logger.Error("failed to parse duration",
-			slog.String("value", val),
-			slog.Any("error", err),
-		)
-		os.Exit(1)
+		cfg.fail(fmt.Errorf("invalid %s %q: %w", name, val, err))
+		return 0
+	}
+
+	return fromString
+}
+
+func (cfg *Config) parseInterval(name string, val string) time.Duration {
+	fromString := cfg.parseDuration(name, val)
+	if fromString == 0 {
+		return MinInterval
 	}
 
 	if fromString < MinInterval {
 		cfg.Logger.Warn(
 			"duration is too low",
+			slog.String("setting", name),
 			slog.String("value", val),
 			slog.String("default value", MinInterval.String()),
 		)
 		fromString = MinInterval
 	}
 
-	cfg.HealthCheckInterval = fromString
+	return fromString
+}
+
+func (cfg *Config) SetMetricInterval(val string) *Config {
+	cfg.MetricInterval = cfg.parseInterval("metric interval", val)
+	return cfg
+}
+
+func (cfg *Config) SetHealthCheckInterval(val string) *Config {
+	cfg.HealthCheckInterval = cfg.parseInterval("health check interval", val)
 	return cfg
 }
 
@@ -111,44 +289,588 @@ func (cfg *Config) SetIdentifier(val string) *Config {
 	return cfg
 }
 
-func (cfg *Config) ApplyStartOverrides(cliArgs cli.Start) *Config {
+func (cfg *Config) SetDefaultOutboxDir() *Config {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cfg.Logger.Warn("failed to get user cache dir, using temp dir", slog.Any("error", err))
+		cacheDir = os.TempDir()
+	}
+
+	cfg.OutboxDir = filepath.Join(cacheDir, "microwatcher", "outbox")
+	return cfg
+}
+
+func (cfg *Config) SetOutboxDir(val string) *Config {
+	cfg.OutboxDir = val
+	return cfg
+}
+
+func (cfg *Config) SetOutboxSegmentSize(megabytes int64) *Config {
+	if megabytes <= 0 {
+		cfg.fail(fmt.Errorf("outbox segment size must be positive, got %d", megabytes))
+	}
+
+	cfg.Outbox.SegmentMaxBytes = megabytes * megabyte
+	return cfg
+}
+
+func (cfg *Config) SetOutboxSegmentAge(val string) *Config {
+	cfg.Outbox.SegmentMaxAge = cfg.parseDuration("outbox segment age", val)
+	return cfg
+}
+
+func (cfg *Config) SetOutboxMaxSize(megabytes int64) *Config {
+	if megabytes <= 0 {
+		cfg.fail(fmt.Errorf("outbox max size must be positive, got %d", megabytes))
+	}
+
+	cfg.Outbox.MaxBytes = megabytes * megabyte
+	return cfg
+}
+
+func (cfg *Config) SetOutboxMaxAge(val string) *Config {
+	cfg.Outbox.MaxAge = cfg.parseDuration("outbox max age", val)
+	return cfg
+}
+
+func (cfg *Config) SetDefaultIngestAddrs() *Config {
+	cfg.IngestAddrs = []string{DefaultIngestAddr}
+	return cfg
+}
+
+func (cfg *Config) SetIngestAddrsFromEnv() *Config {
+	envAddrs := os.Getenv("MW_INGEST_ADDR")
+	if envAddrs != "" {
+		cfg.SetIngestAddrs(strings.Split(envAddrs, ","))
+	}
+	return cfg
+}
+
+func (cfg *Config) SetIngestAddrs(val []string) *Config {
+	addrs := make([]string, 0, len(val))
+	for _, addr := range val {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	if len(addrs) > 0 {
+		cfg.IngestAddrs = addrs
+	}
+	return cfg
+}
+
+func (cfg *Config) SetIngestStrategy(val string) *Config {
+	if val != StrategyFailover && val != StrategyRoundRobin {
+		cfg.fail(fmt.Errorf("invalid ingest strategy %q, expected %s or %s", val, StrategyFailover, StrategyRoundRobin))
+	}
+
+	cfg.IngestStrategy = val
+	return cfg
+}
+
+func (cfg *Config) SetTLS(val TLS) *Config {
+	if (val.CertFile == "") != (val.KeyFile == "") {
+		cfg.fail(fmt.Errorf("tls cert and tls key must be set together"))
+	}
+
+	val.Enabled = val.Enabled || val.CAFile != "" || val.CertFile != "" || val.ServerName != ""
+	cfg.TLS = val
+	return cfg
+}
+
+func (cfg *Config) SetRetry(maxAttempts int, backoffBase string, backoffCap string, jitter float64, codeNames []string) *Config {
+	retryCodes, err := retry.ParseCodes(codeNames)
+	if err != nil {
+		cfg.fail(errors.Join(errors.New("invalid retry codes"), err))
+	}
+
+	if maxAttempts < 1 {
+		cfg.fail(fmt.Errorf("retry max attempts must be at least 1, got %d", maxAttempts))
+	}
+
+	if jitter < 0 || jitter > 1 {
+		cfg.fail(fmt.Errorf("retry jitter must be between 0 and 1, got %v", jitter))
+	}
+
+	cfg.Retry = retry.Policy{
+		MaxAttempts:    maxAttempts,
+		BackoffBase:    cfg.parseDuration("retry backoff base", backoffBase),
+		BackoffCap:     cfg.parseDuration("retry backoff cap", backoffCap),
+		Jitter:         jitter,
+		RetryableCodes: retryCodes,
+	}
+
+	return cfg
+}
+
+func (cfg *Config) SetBreaker(threshold int, cooldown string) *Config {
+	if threshold < 0 {
+		cfg.fail(fmt.Errorf("breaker threshold can't be negative, got %d", threshold))
+	}
+
+	cfg.BreakerThreshold = threshold
+	cfg.BreakerCooldown = cfg.parseDuration("breaker cooldown", cooldown)
+	return cfg
+}
+
+// SetCollectors applies the per collector settings on top of the defaults,
+// collectors without an interval run on every metric interval.
+func (cfg *Config) SetCollectors(val map[string]FileCollector) *Config {
+	defaultTimeout := cfg.parseDuration("collector timeout", DefaultCollectorTimeout)
+
+	cfg.Collectors = make(map[string]CollectorSettings, len(KnownCollectors))
+	for _, name := range KnownCollectors {
+		cfg.Collectors[name] = CollectorSettings{
+			Enabled: !slices.Contains(OptInCollectors, name),
+			Timeout: defaultTimeout,
+		}
+	}
+
+	certificates := cfg.Collectors["certificates"]
+	certificates.Interval = cfg.parseInterval("certificates collector interval", DefaultCertificatesInterval)
+	cfg.Collectors["certificates"] = certificates
+
+	for name, collector := range val {
+		if !slices.Contains(KnownCollectors, name) {
+			cfg.fail(fmt.Errorf("unknown collector %q, expected one of %s", name, strings.Join(KnownCollectors, ", ")))
+			continue
+		}
+
+		cfg.Collectors[name] = cfg.collectorSettings(name, cfg.Collectors[name], collector)
+	}
+
+	if interval := cfg.Collectors["certificates"].Interval; interval > MaxCertificatesInterval {
+		cfg.fail(fmt.Errorf("certificates collector interval can't be above %s, got %s", MaxCertificatesInterval, interval))
+	}
+
+	return cfg
+}
+
+// collectorSettings parses the settings shared by every collector, on top of
+// the defaults.
+func (cfg *Config) collectorSettings(name string, defaults CollectorSettings, val FileCollector) CollectorSettings {
+	settings := defaults
+	if val.Enabled != nil {
+		settings.Enabled = *val.Enabled
+	}
+	if val.Interval != "" {
+		settings.Interval = cfg.parseInterval(name+" collector interval", val.Interval)
+	}
+	if val.Timeout != "" {
+		settings.Timeout = cfg.parseDuration(name+" collector timeout", val.Timeout)
+		if settings.Timeout <= 0 {
+			cfg.fail(fmt.Errorf("%s collector timeout must be positive, got %q", name, val.Timeout))
+		}
+	}
+
+	return settings
+}
+
+func (cfg *Config) SetExecCollectors(val []FileExecCollector) *Config {
+	defaults := CollectorSettings{
+		Enabled: true,
+		Timeout: cfg.parseDuration("collector timeout", DefaultCollectorTimeout),
+	}
+
+	cfg.ExecCollectors = make([]ExecCollector, 0, len(val))
+	for idx, collector := range val {
+		if collector.Name == "" {
+			cfg.fail(fmt.Errorf("exec collector %d has no name", idx))
+			continue
+		}
+		if slices.ContainsFunc(cfg.ExecCollectors, func(c ExecCollector) bool { return c.Name == collector.Name }) {
+			cfg.fail(fmt.Errorf("exec collector %q is defined twice", collector.Name))
+			continue
+		}
+		if len(collector.Command) == 0 {
+			cfg.fail(fmt.Errorf("exec collector %q has no command", collector.Name))
+			continue
+		}
+
+		format := firstSet(collector.Format, metrics.FormatPrometheus)
+		if !slices.Contains(metrics.Formats, format) {
+			cfg.fail(fmt.Errorf("exec collector %q has unknown format %q, expected one of %s", collector.Name, format, strings.Join(metrics.Formats, ", ")))
+			continue
+		}
+
+		cfg.ExecCollectors = append(cfg.ExecCollectors, ExecCollector{
+			CollectorSettings: cfg.collectorSettings("exec "+collector.Name, defaults, collector.FileCollector),
+			Name:              collector.Name,
+			Command:           collector.Command,
+			Format:            format,
+		})
+	}
+
+	return cfg
+}
+
+// logCollectorName keeps names usable as the file name of their offsets.
+var logCollectorName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+func (cfg *Config) SetLogCollectors(val []FileLogCollector) *Config {
+	defaults := CollectorSettings{
+		Enabled: true,
+		Timeout: cfg.parseDuration("collector timeout", DefaultCollectorTimeout),
+	}
+
+	cfg.LogCollectors = make([]LogCollector, 0, len(val))
+	for idx, collector := range val {
+		if !logCollectorName.MatchString(collector.Name) {
+			cfg.fail(fmt.Errorf("log collector %d has an invalid name %q, expected letters, digits, '_', '.' or '-'", idx, collector.Name))
+			continue
+		}
+		if slices.ContainsFunc(cfg.LogCollectors, func(c LogCollector) bool { return c.Name == collector.Name }) {
+			cfg.fail(fmt.Errorf("log collector %q is defined twice", collector.Name))
+			continue
+		}
+		if len(collector.Paths) == 0 {
+			cfg.fail(fmt.Errorf("log collector %q has no paths", collector.Name))
+			continue
+		}
+
+		valid := true
+		for _, path := range collector.Paths {
+			if _, err := filepath.Match(path, ""); err != nil {
+				cfg.fail(fmt.Errorf("log collector %q has an invalid path pattern %q: %w", collector.Name, path, err))
+				valid = false
+			}
+		}
+		if !valid {
+			continue
+		}
+
+		parser, err := systeminformation.NewLogParser(collector.Format, collector.Pattern)
+		if err != nil {
+			cfg.fail(errors.Join(fmt.Errorf("log collector %q has an invalid parser", collector.Name), err))
+			continue
+		}
+
+		cfg.LogCollectors = append(cfg.LogCollectors, LogCollector{
+			CollectorSettings: cfg.collectorSettings("logs "+collector.Name, defaults, collector.FileCollector),
+			Name:              collector.Name,
+			Paths:             collector.Paths,
+			Parser:            parser,
+			FromBeginning:     collector.FromBeginning,
+		})
+	}
+
+	return cfg
+}
+
+func (cfg *Config) SetProbeCollectors(val []FileProbeCollector) *Config {
+	defaults := CollectorSettings{
+		Enabled: true,
+		Timeout: cfg.parseDuration("collector timeout", DefaultCollectorTimeout),
+	}
+
+	cfg.ProbeCollectors = make([]ProbeCollector, 0, len(val))
+	for idx, collector := range val {
+		if collector.Name == "" {
+			cfg.fail(fmt.Errorf("probe %d has no name", idx))
+			continue
+		}
+		if slices.ContainsFunc(cfg.ProbeCollectors, func(c ProbeCollector) bool { return c.Name == collector.Name }) {
+			cfg.fail(fmt.Errorf("probe %q is defined twice", collector.Name))
+			continue
+		}
+		if !slices.Contains(systeminformation.ProbeKinds, collector.Kind) {
+			cfg.fail(fmt.Errorf("probe %q has unknown kind %q, expected one of %s", collector.Name, collector.Kind, strings.Join(systeminformation.ProbeKinds, ", ")))
+			continue
+		}
+		if collector.Target == "" {
+			cfg.fail(fmt.Errorf("probe %q has no target", collector.Name))
+			continue
+		}
+
+		assertions := systeminformation.ProbeAssertions{
+			Status:         collector.ExpectStatus,
+			CertExpiryDays: collector.CertExpiryDays,
+		}
+		if collector.BodyRegex != "" {
+			pattern, err := regexp.Compile(collector.BodyRegex)
+			if err != nil {
+				cfg.fail(fmt.Errorf("probe %q has an invalid body regex %q: %w", collector.Name, collector.BodyRegex, err))
+				continue
+			}
+			assertions.Body = pattern
+		}
+
+		switch collector.Kind {
+		case systeminformation.ProbeHTTP:
+			target, err := url.Parse(collector.Target)
+			if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+				cfg.fail(fmt.Errorf("probe %q has an invalid target %q, expected an http or https url", collector.Name, collector.Target))
+				continue
+			}
+		case systeminformation.ProbeTCP:
+			if _, _, err := net.SplitHostPort(collector.Target); err != nil {
+				cfg.fail(fmt.Errorf("probe %q has an invalid target %q, expected host:port", collector.Name, collector.Target))
+				continue
+			}
+		}
+
+		if collector.Kind != systeminformation.ProbeHTTP && (len(assertions.Status) > 0 || assertions.Body != nil || assertions.CertExpiryDays > 0) {
+			cfg.fail(fmt.Errorf("probe %q sets http assertions on a %s probe", collector.Name, collector.Kind))
+			continue
+		}
+
+		cfg.ProbeCollectors = append(cfg.ProbeCollectors, ProbeCollector{
+			CollectorSettings: cfg.collectorSettings("probe "+collector.Name, defaults, collector.FileCollector),
+			Name:              collector.Name,
+			Kind:              collector.Kind,
+			Target:            collector.Target,
+			Assertions:        assertions,
+		})
+	}
+
+	return cfg
+}
+
+func (cfg *Config) SetScrapeCollectors(val []FileScrapeCollector) *Config {
+	defaults := CollectorSettings{
+		Enabled: true,
+		Timeout: cfg.parseDuration("collector timeout", DefaultCollectorTimeout),
+	}
+
+	compile := func(name string, exprs []string) []*regexp.Regexp {
+		patterns := make([]*regexp.Regexp, 0, len(exprs))
+		for _, expr := range exprs {
+			pattern, err := metrics.CompileAnchored(expr)
+			if err != nil {
+				cfg.fail(fmt.Errorf("scrape collector %q has an invalid pattern %q: %w", name, expr, err))
+				continue
+			}
+			patterns = append(patterns, pattern)
+		}
+		return patterns
+	}
+
+	cfg.ScrapeCollectors = make([]ScrapeCollector, 0, len(val))
+	for idx, collector := range val {
+		if collector.Name == "" {
+			cfg.fail(fmt.Errorf("scrape collector %d has no name", idx))
+			continue
+		}
+		if slices.ContainsFunc(cfg.ScrapeCollectors, func(c ScrapeCollector) bool { return c.Name == collector.Name }) {
+			cfg.fail(fmt.Errorf("scrape collector %q is defined twice", collector.Name))
+			continue
+		}
+
+		target, err := url.Parse(collector.URL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			cfg.fail(fmt.Errorf("scrape collector %q has an invalid url %q", collector.Name, collector.URL))
+			continue
+		}
+
+		rules := make([]metrics.RelabelRule, 0, len(collector.Relabel))
+		for ruleIdx, rule := range collector.Relabel {
+			parsed, err := metrics.NewRelabelRule(rule.SourceLabels, rule.Separator, rule.Regex, rule.TargetLabel, rule.Replacement, rule.Action)
+			if err != nil {
+				cfg.fail(fmt.Errorf("scrape collector %q has an invalid relabel rule %d: %w", collector.Name, ruleIdx, err))
+				continue
+			}
+			rules = append(rules, parsed)
+		}
+
+		cfg.ScrapeCollectors = append(cfg.ScrapeCollectors, ScrapeCollector{
+			CollectorSettings: cfg.collectorSettings("scrape "+collector.Name, defaults, collector.FileCollector),
+			Name:              collector.Name,
+			URL:               collector.URL,
+			Filter: metrics.Filter{
+				Allow: compile(collector.Name, collector.Allow),
+				Deny:  compile(collector.Name, collector.Deny),
+			},
+			Relabel: rules,
+		})
+	}
+
+	return cfg
+}
+
+func (cfg *Config) SetPerCoreCPU(val bool) *Config {
+	cfg.PerCoreCPU = val
+	return cfg
+}
+
+func (cfg *Config) SetDiskFilter(val systeminformation.DiskFilter) *Config {
+	for _, pattern := range val.Patterns() {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			cfg.fail(fmt.Errorf("invalid disk filter pattern %q", pattern))
+		}
+	}
+
+	cfg.DiskFilter = val
+	return cfg
+}
+
+// SetSystemd needs the collectors set, units are only required when the
+// systemd collector is enabled.
+func (cfg *Config) SetSystemd(units []string, journalPriority string) *Config {
+	if cfg.CollectorEnabled("systemd") && len(units) == 0 {
+		cfg.fail(errors.New("systemd collector is enabled without units"))
+	}
+
+	if journalPriority != "" && !slices.Contains(systeminformation.JournalPriorities, journalPriority) {
+		if level, err := strconv.Atoi(journalPriority); err != nil || level < 0 || level >= len(systeminformation.JournalPriorities) {
+			cfg.fail(fmt.Errorf("unknown journal priority %q, expected 0-7 or one of %s", journalPriority, strings.Join(systeminformation.JournalPriorities, ", ")))
+		}
+	}
+
+	cfg.SystemdUnits = units
+	cfg.JournalPriority = journalPriority
+	return cfg
+}
+
+// SetCertificates needs the collectors set, paths or endpoints are only
+// required when the certificates collector is enabled.
+func (cfg *Config) SetCertificates(paths []string, endpoints []string) *Config {
+	if cfg.CollectorEnabled("certificates") && len(paths) == 0 && len(endpoints) == 0 {
+		cfg.fail(errors.New("certificates collector is enabled without paths or endpoints"))
+	}
+
+	for _, path := range paths {
+		if _, err := filepath.Match(path, ""); err != nil {
+			cfg.fail(fmt.Errorf("certificates collector has an invalid path pattern %q: %w", path, err))
+		}
+	}
+
+	for _, endpoint := range endpoints {
+		if _, _, err := net.SplitHostPort(endpoint); err != nil {
+			cfg.fail(fmt.Errorf("certificates collector has an invalid endpoint %q, expected host:port", endpoint))
+		}
+	}
+
+	cfg.CertificatePaths = paths
+	cfg.CertificateEndpoints = endpoints
+	return cfg
+}
+
+func (cfg *Config) SetProcessTop(val int) *Config {
+	if val <= 0 {
+		cfg.fail(fmt.Errorf("process top must be positive, got %d", val))
+	}
+
+	cfg.ProcessTop = val
+	return cfg
+}
+
+func (cfg *Config) SetInContainer(val bool) *Config {
+	cfg.InContainer = val
+	return cfg
+}
+
+// CollectorEnabled reports whether the named collector should run.
+func (cfg *Config) CollectorEnabled(name string) bool {
+	return cfg.Collectors[name].Enabled
+}
+
+func (cfg *Config) applyCredentialOverrides(clientID string, clientSecret string, file *File) *Config {
+	cfg.
+		SetClientID(file.ClientID).
+		SetClientIDFromEnv()
+	if clientID != "" {
+		cfg.SetClientID(clientID)
+	}
+
+	cfg.
+		SetSecret(file.ClientSecret).
+		SetSecretFromEnv()
+	if clientSecret != "" {
+		cfg.SetSecret(clientSecret)
+	}
+
+	return cfg
+}
+
+func (cfg *Config) applyIngestOverrides(cliArgs cli.Ingest, file *File) *Config {
 	cfg.
-		SetMetricInterval(cliArgs.MetricInterval).
-		SetHealthCheckInterval(cliArgs.HealthCheckInterval).
+		SetDefaultIngestAddrs().
+		SetIngestAddrs(file.Ingest.Addrs).
+		SetIngestAddrsFromEnv().
+		SetIngestAddrs(cliArgs.IngestAddr).
+		SetIngestStrategy(firstSet(cliArgs.IngestStrategy, file.Ingest.Strategy, StrategyFailover)).
+		SetTLS(TLS{
+			Enabled:    *firstSet(cliArgs.TLS, file.Ingest.TLS.Enabled, ptr(false)),
+			CAFile:     firstSet(cliArgs.TLSCA, file.Ingest.TLS.CA),
+			CertFile:   firstSet(cliArgs.TLSCert, file.Ingest.TLS.Cert),
+			KeyFile:    firstSet(cliArgs.TLSKey, file.Ingest.TLS.Key),
+			ServerName: firstSet(cliArgs.TLSServerName, file.Ingest.TLS.ServerName),
+		})
+
+	return cfg
+}
+
+func (cfg *Config) applyRetryOverrides(cliArgs cli.Retry, file *File) *Config {
+	cfg.
+		SetRetry(
+			*firstSet(cliArgs.RetryMaxAttempts, file.Retry.MaxAttempts, ptr(DefaultRetryMaxAttempts)),
+			firstSet(cliArgs.RetryBackoffBase, file.Retry.BackoffBase, DefaultRetryBackoffBase),
+			firstSet(cliArgs.RetryBackoffCap, file.Retry.BackoffCap, DefaultRetryBackoffCap),
+			*firstSet(cliArgs.RetryJitter, file.Retry.Jitter, ptr(DefaultRetryJitter)),
+			firstNonEmpty(cliArgs.RetryCodes, file.Retry.Codes, DefaultRetryCodes),
+		).
+		SetBreaker(
+			*firstSet(cliArgs.BreakerThreshold, file.Retry.BreakerThreshold, ptr(DefaultBreakerThreshold)),
+			firstSet(cliArgs.BreakerCooldown, file.Retry.BreakerCooldown, DefaultBreakerCooldown),
+		)
+
+	return cfg
+}
+
+func (cfg *Config) ApplyStartOverrides(cliArgs cli.Start, file *File) *Config {
+	cfg.
+		SetMetricInterval(firstSet(cliArgs.MetricInterval, file.MetricInterval, DefaultMetricInterval)).
+		SetHealthCheckInterval(firstSet(cliArgs.HealthCheckInterval, file.HealthCheckInterval, DefaultHealthCheckInterval)).
+		applyRetryOverrides(cliArgs.Retry, file).
+		applyIngestOverrides(cliArgs.Ingest, file).
 		SetDefaultIdentifier()
 
-	if cliArgs.Identifier != "" {
-		cfg.SetIdentifier(cliArgs.Identifier)
+	if identifier := firstSet(cliArgs.Identifier, file.Identifier); identifier != "" {
+		cfg.SetIdentifier(identifier)
 	}
 
 	cfg.Logger.Info("applying overrides", slog.Any("cliArgs", cliArgs))
 
-	cfg.SetClientIDFromEnv()
-	if cliArgs.ClientID != "" {
-		cfg.SetClientID(cliArgs.ClientID)
-	}
+	cfg.applyCredentialOverrides(cliArgs.ClientID, cliArgs.ClientSecret, file)
 
-	cfg.SetSecretFromEnv()
-	if cliArgs.ClientSecret != "" {
-		cfg.SetSecret(cliArgs.ClientSecret)
+	cfg.
+		SetDefaultOutboxDir().
+		SetOutboxSegmentSize(*firstSet(cliArgs.OutboxSegmentSize, file.Outbox.SegmentSize, ptr[int64](DefaultOutboxSegmentSize))).
+		SetOutboxSegmentAge(firstSet(cliArgs.OutboxSegmentAge, file.Outbox.SegmentAge, DefaultOutboxSegmentAge)).
+		SetOutboxMaxSize(*firstSet(cliArgs.OutboxMaxSize, file.Outbox.MaxSize, ptr[int64](DefaultOutboxMaxSize))).
+		SetOutboxMaxAge(firstSet(cliArgs.OutboxMaxAge, file.Outbox.MaxAge, DefaultOutboxMaxAge))
+	if outboxDir := firstSet(cliArgs.OutboxDir, file.Outbox.Dir); outboxDir != "" {
+		cfg.SetOutboxDir(outboxDir)
 	}
 
+	cfg.
+		SetCollectors(file.Collectors.settings()).
+		SetPerCoreCPU(*firstSet(cliArgs.PerCoreCPU, file.Collectors.CPU.PerCore, ptr(false))).
+		SetDiskFilter(file.Collectors.Disk.filter()).
+		SetProcessTop(*firstSet(file.Collectors.Process.Top, ptr(DefaultProcessTop))).
+		SetInContainer(*firstSet(cliArgs.InContainer, file.InContainer, ptr(false))).
+		SetExecCollectors(file.Collectors.Exec).
+		SetScrapeCollectors(file.Collectors.Scrape).
+		SetLogCollectors(file.Collectors.Logs).
+		SetProbeCollectors(file.Collectors.Probes).
+		SetSystemd(file.Collectors.Systemd.Units, file.Collectors.Systemd.JournalPriority).
+		SetCertificates(file.Collectors.Certificates.Paths, file.Collectors.Certificates.Endpoints)
+
 	return cfg
 }
 
-func (cfg *Config) ApplyCheckOverrides(cliArgs cli.Check) *Config {
+func (cfg *Config) ApplyCheckOverrides(cliArgs cli.Check, file *File) *Config {
 	cfg.
+		applyRetryOverrides(cliArgs.Retry, file).
+		applyIngestOverrides(cliArgs.Ingest, file).
 		SetDefaultIdentifier()
 
-	cfg.SetClientIDFromEnv()
-	if cliArgs.ClientID != "" {
-		cfg.SetClientID(cliArgs.ClientID)
+	if file.Identifier != "" {
+		cfg.SetIdentifier(file.Identifier)
 	}
 
-	cfg.SetSecretFromEnv()
-	if cliArgs.ClientSecret != "" {
-		cfg.SetSecret(cliArgs.ClientSecret)
-	}
+	cfg.applyCredentialOverrides(cliArgs.ClientID, cliArgs.ClientSecret, file)
 
 	return cfg
 }
@@ -0,0 +1,308 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/microwatcher/agent/internal/systeminformation"
+	"gopkg.in/yaml.v3"
+)
+
+// File mirrors the command line flags, anything left out falls back to the
+// defaults.
+//
+//	metric_interval: 10s
+//	health_check_interval: 30s
+//	identifier: edge-01
+//	in_container: true
+//	client_id: 0190b5a4-...
+//	client_secret: mw_...
+//	ingest:
+//	  addrs: [ingest-a:50051, ingest-b:50051]
+//	  strategy: failover
+//	  tls:
+//	    ca: /etc/microwatcher/ca.pem
+//	outbox:
+//	  dir: /var/lib/microwatcher/outbox
+//	  max_size_mb: 512
+//	retry:
+//	  max_attempts: 6
+//	collectors:
+//	  cpu:
+//	    per_core: true
+//	  disk:
+//	    interval: 1m
+//	    timeout: 10s
+//	    exclude_mountpoints: [/boot/*]
+//	  network:
+//	    enabled: false
+//	  process:
+//	    enabled: true
+//	    top: 5
+//	  exec:
+//	    - name: queue_depth
+//	      command: [/usr/local/bin/queue-depth, --json]
+//	      format: json
+//	      interval: 30s
+//	  scrape:
+//	    - name: caddy
+//	      url: http://127.0.0.1:2019/metrics
+//	      interval: 15s
+//	      allow: ["caddy_http_.*"]
+//	      deny: [".*_bucket"]
+//	      relabel:
+//	        - source_labels: [server]
+//	          target_label: site
+//	  systemd:
+//	    enabled: true
+//	    units: [nginx.service, postgresql.service]
+//	    journal_priority: warning
+//	  certificates:
+//	    enabled: true
+//	    paths: [/etc/nginx/certs, /etc/ssl/private/*.pem]
+//	    endpoints: [localhost:443]
+//	  logs:
+//	    - name: nginx
+//	      paths: [/var/log/nginx/*.log]
+//	      format: regex
+//	      pattern: '^(?P<remote>\S+) .* "(?P<message>[^"]*)" (?P<status>\d+)'
+//	      interval: 2s
+//	  probes:
+//	    - name: api
+//	      kind: http
+//	      target: http://localhost:8080/healthz
+//	      interval: 30s
+//	      timeout: 500ms
+//	      expect_status: [200]
+//	      body_regex: '"status":\s*"ok"'
+//	    - name: postgres
+//	      kind: tcp
+//	      target: localhost:5432
+type File struct {
+	MetricInterval      string         `yaml:"metric_interval"`
+	HealthCheckInterval string         `yaml:"health_check_interval"`
+	Identifier          string         `yaml:"identifier"`
+	InContainer         *bool          `yaml:"in_container"`
+	ClientID            string         `yaml:"client_id"`
+	ClientSecret        string         `yaml:"client_secret"`
+	Ingest              FileIngest     `yaml:"ingest"`
+	Outbox              FileOutbox     `yaml:"outbox"`
+	Retry               FileRetry      `yaml:"retry"`
+	Collectors          FileCollectors `yaml:"collectors"`
+}
+
+type FileIngest struct {
+	Addrs    []string `yaml:"addrs"`
+	Strategy string   `yaml:"strategy"`
+	TLS      FileTLS  `yaml:"tls"`
+}
+
+type FileTLS struct {
+	Enabled    *bool  `yaml:"enabled"`
+	CA         string `yaml:"ca"`
+	Cert       string `yaml:"cert"`
+	Key        string `yaml:"key"`
+	ServerName string `yaml:"server_name"`
+}
+
+type FileOutbox struct {
+	Dir         string `yaml:"dir"`
+	SegmentSize *int64 `yaml:"segment_size_mb"`
+	SegmentAge  string `yaml:"segment_age"`
+	MaxSize     *int64 `yaml:"max_size_mb"`
+	MaxAge      string `yaml:"max_age"`
+}
+
+type FileRetry struct {
+	MaxAttempts      *int     `yaml:"max_attempts"`
+	BackoffBase      string   `yaml:"backoff_base"`
+	BackoffCap       string   `yaml:"backoff_cap"`
+	Jitter           *float64 `yaml:"jitter"`
+	Codes            []string `yaml:"codes"`
+	BreakerThreshold *int     `yaml:"breaker_threshold"`
+	BreakerCooldown  string   `yaml:"breaker_cooldown"`
+}
+
+type FileCollector struct {
+	Enabled *bool `yaml:"enabled"`
+	// defaults to every metric interval
+	Interval string `yaml:"interval"`
+	Timeout  string `yaml:"timeout"`
+}
+
+type FileCPUCollector struct {
+	FileCollector `yaml:",inline"`
+	PerCore       *bool `yaml:"per_core"`
+}
+
+// FileDiskCollector filters the reported partitions. Setting exclude_fstypes
+// or exclude_mountpoints replaces the defaults, which drop virtual
+// filesystems and snap mounts, setting include_fstypes drops the default
+// fstype exclusions.
+type FileDiskCollector struct {
+	FileCollector      `yaml:",inline"`
+	IncludeFSTypes     []string `yaml:"include_fstypes"`
+	ExcludeFSTypes     []string `yaml:"exclude_fstypes"`
+	IncludeMountpoints []string `yaml:"include_mountpoints"`
+	ExcludeMountpoints []string `yaml:"exclude_mountpoints"`
+	IncludeDevices     []string `yaml:"include_devices"`
+	ExcludeDevices     []string `yaml:"exclude_devices"`
+}
+
+func (fd FileDiskCollector) filter() systeminformation.DiskFilter {
+	filter := systeminformation.DefaultDiskFilter()
+	if len(fd.IncludeFSTypes) > 0 {
+		filter.ExcludeFSTypes = nil
+	}
+
+	filter.IncludeFSTypes = fd.IncludeFSTypes
+	if fd.ExcludeFSTypes != nil {
+		filter.ExcludeFSTypes = fd.ExcludeFSTypes
+	}
+	filter.IncludeMountpoints = fd.IncludeMountpoints
+	if fd.ExcludeMountpoints != nil {
+		filter.ExcludeMountpoints = fd.ExcludeMountpoints
+	}
+	filter.IncludeDevices = fd.IncludeDevices
+	filter.ExcludeDevices = fd.ExcludeDevices
+
+	return filter
+}
+
+// FileProcessCollector reports the top processes by cpu and by memory, it
+// is disabled unless enabled explicitly.
+type FileProcessCollector struct {
+	FileCollector `yaml:",inline"`
+	Top           *int `yaml:"top"`
+}
+
+// FileExecCollector runs a command and ships what it prints as custom
+// metrics, format is prometheus (the default) or json.
+type FileExecCollector struct {
+	FileCollector `yaml:",inline"`
+	Name          string   `yaml:"name"`
+	Command       []string `yaml:"command"`
+	Format        string   `yaml:"format"`
+}
+
+// FileRelabelRule follows Prometheus' metric_relabel_configs, the metric
+// name is the __name__ label.
+type FileRelabelRule struct {
+	SourceLabels []string `yaml:"source_labels"`
+	Separator    string   `yaml:"separator"`
+	Regex        string   `yaml:"regex"`
+	TargetLabel  string   `yaml:"target_label"`
+	Replacement  string   `yaml:"replacement"`
+	Action       string   `yaml:"action"`
+}
+
+// FileScrapeCollector scrapes a Prometheus endpoint, allow and deny are
+// regexes matched against the whole metric name.
+type FileScrapeCollector struct {
+	FileCollector `yaml:",inline"`
+	Name          string            `yaml:"name"`
+	URL           string            `yaml:"url"`
+	Allow         []string          `yaml:"allow"`
+	Deny          []string          `yaml:"deny"`
+	Relabel       []FileRelabelRule `yaml:"relabel"`
+}
+
+// FileLogCollector tails the files matching paths, format is raw (the
+// default), json or regex with a pattern of named groups.
+type FileLogCollector struct {
+	FileCollector `yaml:",inline"`
+	Name          string   `yaml:"name"`
+	Paths         []string `yaml:"paths"`
+	Format        string   `yaml:"format"`
+	Pattern       string   `yaml:"pattern"`
+	FromBeginning bool     `yaml:"from_beginning"`
+}
+
+// FileSystemdCollector reports the state of units, it is disabled unless
+// enabled explicitly. With journal_priority set their journal entries up to
+// that priority are shipped as logs.
+type FileSystemdCollector struct {
+	FileCollector   `yaml:",inline"`
+	Units           []string `yaml:"units"`
+	JournalPriority string   `yaml:"journal_priority"`
+}
+
+// FileProbeCollector checks that target is reachable, kind is http, tcp or
+// dns. expect_status, body_regex and cert_expiry_days only apply to http
+// probes, which otherwise succeed on any 2xx or 3xx status.
+type FileProbeCollector struct {
+	FileCollector  `yaml:",inline"`
+	Name           string `yaml:"name"`
+	Kind           string `yaml:"kind"`
+	Target         string `yaml:"target"`
+	ExpectStatus   []int  `yaml:"expect_status"`
+	BodyRegex      string `yaml:"body_regex"`
+	CertExpiryDays int    `yaml:"cert_expiry_days"`
+}
+
+// FileCertificatesCollector reports the certificates in the PEM files
+// matching paths and those the host:port endpoints serve, it is disabled
+// unless enabled explicitly and runs hourly by default.
+type FileCertificatesCollector struct {
+	FileCollector `yaml:",inline"`
+	Paths         []string `yaml:"paths"`
+	Endpoints     []string `yaml:"endpoints"`
+}
+
+type FileCollectors struct {
+	CPU          FileCPUCollector          `yaml:"cpu"`
+	Memory       FileCollector             `yaml:"memory"`
+	Disk         FileDiskCollector         `yaml:"disk"`
+	Network      FileCollector             `yaml:"network"`
+	System       FileCollector             `yaml:"system"`
+	Process      FileProcessCollector      `yaml:"process"`
+	Sensors      FileCollector             `yaml:"sensors"`
+	Cgroup       FileCollector             `yaml:"cgroup"`
+	Systemd      FileSystemdCollector      `yaml:"systemd"`
+	Certificates FileCertificatesCollector `yaml:"certificates"`
+	Exec         []FileExecCollector       `yaml:"exec"`
+	Scrape       []FileScrapeCollector     `yaml:"scrape"`
+	Logs         []FileLogCollector        `yaml:"logs"`
+	Probes       []FileProbeCollector      `yaml:"probes"`
+}
+
+// settings maps every collector name to its common settings.
+func (fc FileCollectors) settings() map[string]FileCollector {
+	return map[string]FileCollector{
+		"cpu":          fc.CPU.FileCollector,
+		"memory":       fc.Memory,
+		"disk":         fc.Disk.FileCollector,
+		"network":      fc.Network,
+		"system":       fc.System,
+		"process":      fc.Process.FileCollector,
+		"sensors":      fc.Sensors,
+		"cgroup":       fc.Cgroup,
+		"systemd":      fc.Systemd.FileCollector,
+		"certificates": fc.Certificates.FileCollector,
+	}
+}
+
+// LoadFile reads the config file at path, an empty path yields an empty
+// file so every setting falls back to flags, env vars and defaults.
+func LoadFile(path string) (*File, error) {
+	var file File
+	if path == "" {
+		return &file, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to read config file"), err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.Join(fmt.Errorf("failed to parse config file %s", path), err)
+	}
+
+	return &file, nil
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type jsonMetric struct {
+	Name   string            `json:"name"`
+	Value  *float64          `json:"value"`
+	Type   string            `json:"type"`
+	Labels map[string]string `json:"labels"`
+	// unix milliseconds
+	Timestamp int64 `json:"timestamp"`
+}
+
+// ParseJSONLines parses one {"name", "value", "labels"} object per line,
+// blank lines are skipped and type defaults to gauge.
+func ParseJSONLines(in io.Reader) ([]Metric, error) {
+	var metrics []Metric
+
+	scanner := bufio.NewScanner(in)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var parsed jsonMetric
+		if err := json.Unmarshal([]byte(text), &parsed); err != nil {
+			return nil, errors.Join(fmt.Errorf("invalid metric on line %d", line), err)
+		}
+		if parsed.Name == "" {
+			return nil, fmt.Errorf("metric on line %d has no name", line)
+		}
+		if parsed.Value == nil {
+			return nil, fmt.Errorf("metric %s on line %d has no value", parsed.Name, line)
+		}
+
+		metric := Metric{
+			Name:   parsed.Name,
+			Type:   parsed.Type,
+			Value:  *parsed.Value,
+			Labels: parsed.Labels,
+		}
+		if metric.Type == "" {
+			metric.Type = TypeGauge
+		}
+		if parsed.Timestamp > 0 {
+			metric.Timestamp = time.UnixMilli(parsed.Timestamp)
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	return metrics, scanner.Err()
+}
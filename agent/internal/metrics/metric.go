@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"time"
+)
+
+const (
+	FormatPrometheus = "prometheus"
+	FormatJSON       = "json"
+)
+
+var Formats = []string{FormatPrometheus, FormatJSON}
+
+const (
+	TypeGauge   = "gauge"
+	TypeCounter = "counter"
+	TypeUntyped = "untyped"
+)
+
+// Metric is a single sample of a custom metric, histograms and summaries
+// are flattened into their _bucket, _sum and _count series the same way
+// Prometheus stores them.
+type Metric struct {
+	// what produced the metric, e.g. the exec collector name
+	Source string
+	Name   string
+	Type   string
+	Value  float64
+	Labels map[string]string
+	// zero when the source didn't set one, the collection time is used
+	Timestamp time.Time
+}
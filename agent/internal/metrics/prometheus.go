@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// ParsePrometheus parses the Prometheus text exposition format.
+func ParsePrometheus(in io.Reader) ([]Metric, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(in)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromFamilies(families), nil
+}
+
+// FromFamilies flattens parsed metric families, sorted by name so the
+// output is stable.
+func FromFamilies(families map[string]*dto.MetricFamily) []Metric {
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var metrics []Metric
+	for _, name := range names {
+		family := families[name]
+		for _, m := range family.GetMetric() {
+			metrics = append(metrics, fromMetric(name, family.GetType(), m)...)
+		}
+	}
+
+	return metrics
+}
+
+func labelsOf(m *dto.Metric) map[string]string {
+	labels := make(map[string]string, len(m.GetLabel()))
+	for _, pair := range m.GetLabel() {
+		labels[pair.GetName()] = pair.GetValue()
+	}
+
+	return labels
+}
+
+// withLabel copies labels and adds one more, used for le and quantile.
+func withLabel(labels map[string]string, name string, value string) map[string]string {
+	copied := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		copied[k] = v
+	}
+	copied[name] = value
+
+	return copied
+}
+
+func formatFloat(val float64) string {
+	if math.IsInf(val, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
+
+func fromMetric(name string, kind dto.MetricType, m *dto.Metric) []Metric {
+	labels := labelsOf(m)
+
+	var timestamp time.Time
+	if m.TimestampMs != nil {
+		timestamp = time.UnixMilli(m.GetTimestampMs())
+	}
+
+	sample := func(name string, kind string, value float64, labels map[string]string) Metric {
+		return Metric{Name: name, Type: kind, Value: value, Labels: labels, Timestamp: timestamp}
+	}
+
+	switch kind {
+	case dto.MetricType_COUNTER:
+		return []Metric{sample(name, TypeCounter, m.GetCounter().GetValue(), labels)}
+	case dto.MetricType_GAUGE:
+		return []Metric{sample(name, TypeGauge, m.GetGauge().GetValue(), labels)}
+	case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+		histogram := m.GetHistogram()
+		metrics := []Metric{
+			sample(name+"_sum", TypeCounter, histogram.GetSampleSum(), labels),
+			sample(name+"_count", TypeCounter, float64(histogram.GetSampleCount()), labels),
+		}
+
+		hasInf := false
+		for _, bucket := range histogram.GetBucket() {
+			hasInf = hasInf || math.IsInf(bucket.GetUpperBound(), 1)
+			metrics = append(metrics, sample(name+"_bucket", TypeCounter,
+				float64(bucket.GetCumulativeCount()),
+				withLabel(labels, "le", formatFloat(bucket.GetUpperBound())),
+			))
+		}
+		// the text format allows leaving out the +Inf bucket, it's the count
+		if !hasInf {
+			metrics = append(metrics, sample(name+"_bucket", TypeCounter,
+				float64(histogram.GetSampleCount()),
+				withLabel(labels, "le", "+Inf"),
+			))
+		}
+
+		return metrics
+	case dto.MetricType_SUMMARY:
+		summary := m.GetSummary()
+		metrics := []Metric{
+			sample(name+"_sum", TypeCounter, summary.GetSampleSum(), labels),
+			sample(name+"_count", TypeCounter, float64(summary.GetSampleCount()), labels),
+		}
+		for _, quantile := range summary.GetQuantile() {
+			metrics = append(metrics, sample(name, TypeGauge,
+				quantile.GetValue(),
+				withLabel(labels, "quantile", formatFloat(quantile.GetQuantile())),
+			))
+		}
+
+		return metrics
+	default:
+		return []Metric{sample(name, TypeUntyped, m.GetUntyped().GetValue(), labels)}
+	}
+}
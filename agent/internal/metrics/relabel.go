@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	RelabelReplace   = "replace"
+	RelabelKeep      = "keep"
+	RelabelDrop      = "drop"
+	RelabelLabelDrop = "labeldrop"
+	RelabelLabelKeep = "labelkeep"
+)
+
+var RelabelActions = []string{RelabelReplace, RelabelKeep, RelabelDrop, RelabelLabelDrop, RelabelLabelKeep}
+
+// NameLabel holds the metric name while relabeling, as in Prometheus.
+const NameLabel = "__name__"
+
+// CompileAnchored compiles expr so it has to match the whole value, the
+// way Prometheus treats every regex in its config.
+func CompileAnchored(expr string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + expr + ")$")
+}
+
+// Filter keeps metrics by name, a metric passes when it matches one of the
+// allow patterns, or there are none, and none of the deny patterns.
+type Filter struct {
+	Allow []*regexp.Regexp
+	Deny  []*regexp.Regexp
+}
+
+func (f Filter) Match(name string) bool {
+	matches := func(patterns []*regexp.Regexp) bool {
+		for _, pattern := range patterns {
+			if pattern.MatchString(name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(f.Allow) > 0 && !matches(f.Allow) {
+		return false
+	}
+
+	return !matches(f.Deny)
+}
+
+// RelabelRule is a subset of Prometheus' metric_relabel_configs.
+type RelabelRule struct {
+	SourceLabels []string
+	Separator    string
+	Regex        *regexp.Regexp
+	TargetLabel  string
+	Replacement  string
+	Action       string
+}
+
+// NewRelabelRule fills in the Prometheus defaults and checks the rule is
+// complete for its action.
+func NewRelabelRule(sourceLabels []string, separator string, regex string, targetLabel string, replacement string, action string) (RelabelRule, error) {
+	rule := RelabelRule{
+		SourceLabels: sourceLabels,
+		Separator:    separator,
+		TargetLabel:  targetLabel,
+		Replacement:  replacement,
+		Action:       action,
+	}
+
+	if rule.Separator == "" {
+		rule.Separator = ";"
+	}
+	if rule.Replacement == "" {
+		rule.Replacement = "$1"
+	}
+	if rule.Action == "" {
+		rule.Action = RelabelReplace
+	}
+	if regex == "" {
+		regex = "(.*)"
+	}
+
+	compiled, err := CompileAnchored(regex)
+	if err != nil {
+		return RelabelRule{}, fmt.Errorf("invalid regex %q: %w", regex, err)
+	}
+	rule.Regex = compiled
+
+	switch rule.Action {
+	case RelabelReplace:
+		if rule.TargetLabel == "" {
+			return RelabelRule{}, fmt.Errorf("%s needs a target_label", rule.Action)
+		}
+	case RelabelKeep, RelabelDrop:
+		if len(rule.SourceLabels) == 0 {
+			return RelabelRule{}, fmt.Errorf("%s needs source_labels", rule.Action)
+		}
+	case RelabelLabelDrop, RelabelLabelKeep:
+	default:
+		return RelabelRule{}, fmt.Errorf("unknown action %q, expected one of %s", rule.Action, strings.Join(RelabelActions, ", "))
+	}
+
+	return rule, nil
+}
+
+// Relabel applies rules in order, false means the metric was dropped. Labels
+// starting with "__" are removed afterwards.
+func Relabel(metric Metric, rules []RelabelRule) (Metric, bool) {
+	if len(rules) == 0 {
+		return metric, true
+	}
+
+	labels := make(map[string]string, len(metric.Labels)+1)
+	for k, v := range metric.Labels {
+		labels[k] = v
+	}
+	labels[NameLabel] = metric.Name
+
+	for _, rule := range rules {
+		values := make([]string, len(rule.SourceLabels))
+		for idx, name := range rule.SourceLabels {
+			values[idx] = labels[name]
+		}
+		value := strings.Join(values, rule.Separator)
+
+		switch rule.Action {
+		case RelabelKeep:
+			if !rule.Regex.MatchString(value) {
+				return Metric{}, false
+			}
+		case RelabelDrop:
+			if rule.Regex.MatchString(value) {
+				return Metric{}, false
+			}
+		case RelabelLabelDrop:
+			for name := range labels {
+				if name != NameLabel && rule.Regex.MatchString(name) {
+					delete(labels, name)
+				}
+			}
+		case RelabelLabelKeep:
+			for name := range labels {
+				if name != NameLabel && !rule.Regex.MatchString(name) {
+					delete(labels, name)
+				}
+			}
+		case RelabelReplace:
+			match := rule.Regex.FindStringSubmatchIndex(value)
+			if match == nil {
+				continue
+			}
+
+			target := string(rule.Regex.ExpandString(nil, rule.TargetLabel, value, match))
+			replaced := string(rule.Regex.ExpandString(nil, rule.Replacement, value, match))
+			if replaced == "" {
+				delete(labels, target)
+			} else {
+				labels[target] = replaced
+			}
+		}
+	}
+
+	metric.Name = labels[NameLabel]
+	if metric.Name == "" {
+		return Metric{}, false
+	}
+
+	metric.Labels = make(map[string]string, len(labels))
+	for name, value := range labels {
+		if !strings.HasPrefix(name, "__") {
+			metric.Labels[name] = value
+		}
+	}
+
+	return metric, true
+}
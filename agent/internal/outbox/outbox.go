@@ -0,0 +1,504 @@
+package outbox
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	segmentExt = ".seg"
+	cursorFile = "cursor"
+
+	// length + crc32
+	recordHeaderSize = 8
+	// anything bigger than this is treated as corruption
+	maxRecordSize = 64 << 20
+)
+
+var ErrRecordTooLarge = errors.New("record is too large")
+
+type Options struct {
+	// SegmentMaxBytes rotates the active segment once it grows past this size
+	SegmentMaxBytes int64
+	// SegmentMaxAge rotates the active segment once it is older than this
+	SegmentMaxAge time.Duration
+	// MaxBytes evicts the oldest segments once the outbox grows past this size
+	MaxBytes int64
+	// MaxAge evicts segments whose newest record is older than this
+	MaxAge time.Duration
+}
+
+type Position struct {
+	Segment uint64
+	Offset  int64
+}
+
+type segment struct {
+	seq     uint64
+	size    int64
+	modTime time.Time
+}
+
+// Outbox is a disk backed, append only queue split into segment files.
+//
+// Records are read in the order they were appended and are only removed
+// from disk once they are committed, so pending data survives restarts.
+type Outbox struct {
+	mu       sync.Mutex
+	dir      string
+	opts     Options
+	logger   *slog.Logger
+	segments []segment
+	active   *os.File
+	created  time.Time
+	cursor   Position
+}
+
+func Open(dir string, opts Options, logger *slog.Logger) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, errors.Join(errors.New("failed to create outbox directory"), err)
+	}
+
+	ob := &Outbox{
+		dir:    dir,
+		opts:   opts,
+		logger: logger,
+	}
+
+	if err := ob.loadSegments(); err != nil {
+		return nil, err
+	}
+
+	if err := ob.loadCursor(); err != nil {
+		return nil, err
+	}
+
+	// always start writing to a fresh segment, a previous run may have left a
+	// partially written record at the end of its last segment
+	if err := ob.rotate(); err != nil {
+		return nil, err
+	}
+
+	ob.enforceLimits(time.Now())
+
+	return ob, nil
+}
+
+func (ob *Outbox) segmentPath(seq uint64) string {
+	return filepath.Join(ob.dir, fmt.Sprintf("%020d%s", seq, segmentExt))
+}
+
+func (ob *Outbox) loadSegments() error {
+	entries, err := os.ReadDir(ob.dir)
+	if err != nil {
+		return errors.Join(errors.New("failed to read outbox directory"), err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			ob.logger.Warn("ignoring unknown file in outbox", slog.String("file", name))
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return errors.Join(errors.New("failed to stat segment"), err)
+		}
+
+		if info.Size() == 0 {
+			_ = os.Remove(ob.segmentPath(seq))
+			continue
+		}
+
+		ob.segments = append(ob.segments, segment{
+			seq:     seq,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+
+	slices.SortFunc(ob.segments, func(a, b segment) int {
+		switch {
+		case a.seq < b.seq:
+			return -1
+		case a.seq > b.seq:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return nil
+}
+
+func (ob *Outbox) loadCursor() error {
+	data, err := os.ReadFile(filepath.Join(ob.dir, cursorFile))
+	if errors.Is(err, os.ErrNotExist) {
+		ob.resetCursor()
+		return nil
+	}
+	if err != nil {
+		return errors.Join(errors.New("failed to read outbox cursor"), err)
+	}
+
+	if len(data) != 16 {
+		ob.logger.Warn("outbox cursor is corrupted, starting from the oldest segment")
+		ob.resetCursor()
+		return nil
+	}
+
+	ob.cursor = Position{
+		Segment: binary.BigEndian.Uint64(data[:8]),
+		Offset:  int64(binary.BigEndian.Uint64(data[8:])),
+	}
+
+	// the segment the cursor points to may have been removed in the meantime
+	switch {
+	case len(ob.segments) == 0, ob.cursor.Segment < ob.segments[0].seq:
+		ob.resetCursor()
+	case ob.cursor.Segment > ob.segments[len(ob.segments)-1].seq:
+		last := ob.segments[len(ob.segments)-1]
+		ob.cursor = Position{Segment: last.seq, Offset: last.size}
+	}
+
+	return nil
+}
+
+func (ob *Outbox) resetCursor() {
+	ob.cursor = Position{}
+	if len(ob.segments) > 0 {
+		ob.cursor.Segment = ob.segments[0].seq
+	}
+}
+
+func (ob *Outbox) saveCursor() error {
+	var data [16]byte
+	binary.BigEndian.PutUint64(data[:8], ob.cursor.Segment)
+	binary.BigEndian.PutUint64(data[8:], uint64(ob.cursor.Offset))
+
+	tmp := filepath.Join(ob.dir, cursorFile+".tmp")
+	if err := os.WriteFile(tmp, data[:], 0o640); err != nil {
+		return errors.Join(errors.New("failed to write outbox cursor"), err)
+	}
+
+	if err := os.Rename(tmp, filepath.Join(ob.dir, cursorFile)); err != nil {
+		return errors.Join(errors.New("failed to write outbox cursor"), err)
+	}
+
+	return nil
+}
+
+func (ob *Outbox) rotate() error {
+	if ob.active != nil {
+		if err := ob.active.Close(); err != nil {
+			return errors.Join(errors.New("failed to close segment"), err)
+		}
+		ob.active = nil
+	}
+
+	var seq uint64 = 1
+	if len(ob.segments) > 0 {
+		seq = ob.segments[len(ob.segments)-1].seq + 1
+	}
+
+	f, err := os.OpenFile(ob.segmentPath(seq), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return errors.Join(errors.New("failed to create segment"), err)
+	}
+
+	now := time.Now()
+	ob.active = f
+	ob.created = now
+	ob.segments = append(ob.segments, segment{seq: seq, modTime: now})
+
+	if len(ob.segments) == 1 {
+		ob.cursor = Position{Segment: seq}
+	}
+
+	return nil
+}
+
+func (ob *Outbox) activeSegment() *segment {
+	return &ob.segments[len(ob.segments)-1]
+}
+
+// Append durably writes a record at the end of the outbox.
+func (ob *Outbox) Append(record []byte) error {
+	if len(record) > maxRecordSize {
+		return ErrRecordTooLarge
+	}
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	if ob.active == nil {
+		return errors.New("outbox is closed")
+	}
+
+	now := time.Now()
+	active := ob.activeSegment()
+	if active.size > 0 &&
+		((ob.opts.SegmentMaxBytes > 0 && active.size >= ob.opts.SegmentMaxBytes) ||
+			(ob.opts.SegmentMaxAge > 0 && now.Sub(ob.created) >= ob.opts.SegmentMaxAge)) {
+		if err := ob.rotate(); err != nil {
+			return err
+		}
+		active = ob.activeSegment()
+	}
+
+	buf := make([]byte, recordHeaderSize+len(record))
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(record)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(record))
+	copy(buf[recordHeaderSize:], record)
+
+	if _, err := ob.active.Write(buf); err != nil {
+		return errors.Join(errors.New("failed to write record"), err)
+	}
+
+	if err := ob.active.Sync(); err != nil {
+		return errors.Join(errors.New("failed to sync segment"), err)
+	}
+
+	active.size += int64(len(buf))
+	active.modTime = now
+
+	ob.enforceLimits(now)
+
+	return nil
+}
+
+// enforceLimits drops the oldest segments until the outbox is back within
+// its size and age limits. The active segment is never dropped.
+func (ob *Outbox) enforceLimits(now time.Time) {
+	for len(ob.segments) > 1 {
+		oldest := ob.segments[0]
+
+		tooOld := ob.opts.MaxAge > 0 && now.Sub(oldest.modTime) > ob.opts.MaxAge
+		tooBig := ob.opts.MaxBytes > 0 && ob.totalBytes() > ob.opts.MaxBytes
+		if !tooOld && !tooBig {
+			return
+		}
+
+		ob.logger.Warn("evicting outbox segment",
+			slog.Uint64("segment", oldest.seq),
+			slog.Int64("size", oldest.size),
+			slog.Bool("too old", tooOld),
+			slog.Bool("too big", tooBig),
+		)
+
+		ob.dropOldest()
+	}
+}
+
+func (ob *Outbox) totalBytes() int64 {
+	var total int64
+	for _, seg := range ob.segments {
+		total += seg.size
+	}
+	return total
+}
+
+func (ob *Outbox) dropOldest() {
+	oldest := ob.segments[0]
+	if err := os.Remove(ob.segmentPath(oldest.seq)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		ob.logger.Error("failed to remove segment",
+			slog.Uint64("segment", oldest.seq),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	ob.segments = ob.segments[1:]
+
+	if ob.cursor.Segment <= oldest.seq {
+		ob.cursor = Position{Segment: ob.segments[0].seq}
+		if err := ob.saveCursor(); err != nil {
+			ob.logger.Error("failed to save cursor", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// Peek returns up to max records starting at the oldest uncommitted one,
+// along with the position to Commit once they have been handled.
+func (ob *Outbox) Peek(max int) ([][]byte, Position, error) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	var records [][]byte
+	pos := ob.cursor
+
+	for _, seg := range ob.segments {
+		if seg.seq < pos.Segment {
+			continue
+		}
+		if len(records) >= max {
+			break
+		}
+		if seg.seq > pos.Segment {
+			pos = Position{Segment: seg.seq}
+		}
+
+		read, offset, err := ob.readSegment(seg, pos.Offset, max-len(records))
+		if err != nil {
+			return nil, ob.cursor, err
+		}
+
+		records = append(records, read...)
+		pos.Offset = offset
+	}
+
+	return records, pos, nil
+}
+
+func (ob *Outbox) readSegment(seg segment, offset int64, max int) ([][]byte, int64, error) {
+	if offset >= seg.size {
+		return nil, offset, nil
+	}
+
+	f, err := os.Open(ob.segmentPath(seg.seq))
+	if err != nil {
+		return nil, offset, errors.Join(errors.New("failed to open segment"), err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, errors.Join(errors.New("failed to seek segment"), err)
+	}
+
+	reader := bufio.NewReader(io.LimitReader(f, seg.size-offset))
+
+	var records [][]byte
+	var header [recordHeaderSize]byte
+	for len(records) < max {
+		if _, err := io.ReadFull(reader, header[:]); err != nil {
+			if !errors.Is(err, io.EOF) {
+				ob.skipCorrupted(seg, offset, err)
+				return records, seg.size, nil
+			}
+			break
+		}
+
+		size := binary.BigEndian.Uint32(header[:4])
+		if size > maxRecordSize {
+			ob.skipCorrupted(seg, offset, ErrRecordTooLarge)
+			return records, seg.size, nil
+		}
+
+		record := make([]byte, size)
+		if _, err := io.ReadFull(reader, record); err != nil {
+			ob.skipCorrupted(seg, offset, err)
+			return records, seg.size, nil
+		}
+
+		if crc32.ChecksumIEEE(record) != binary.BigEndian.Uint32(header[4:]) {
+			ob.skipCorrupted(seg, offset, errors.New("checksum mismatch"))
+			return records, seg.size, nil
+		}
+
+		records = append(records, record)
+		offset += int64(recordHeaderSize) + int64(size)
+	}
+
+	return records, offset, nil
+}
+
+func (ob *Outbox) skipCorrupted(seg segment, offset int64, err error) {
+	ob.logger.Error("skipping corrupted outbox segment tail",
+		slog.Uint64("segment", seg.seq),
+		slog.Int64("offset", offset),
+		slog.Int64("dropped", seg.size-offset),
+		slog.String("error", err.Error()),
+	)
+}
+
+// Commit marks every record before pos as handled, removing fully consumed
+// segments from disk.
+func (ob *Outbox) Commit(pos Position) error {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	if pos.Segment < ob.cursor.Segment ||
+		(pos.Segment == ob.cursor.Segment && pos.Offset <= ob.cursor.Offset) {
+		return nil
+	}
+
+	ob.cursor = pos
+
+	for len(ob.segments) > 1 {
+		oldest := ob.segments[0]
+		consumed := oldest.seq < pos.Segment ||
+			(oldest.seq == pos.Segment && pos.Offset >= oldest.size)
+		if !consumed {
+			break
+		}
+
+		if err := os.Remove(ob.segmentPath(oldest.seq)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return errors.Join(errors.New("failed to remove segment"), err)
+		}
+
+		ob.segments = ob.segments[1:]
+		if ob.cursor.Segment <= oldest.seq {
+			ob.cursor = Position{Segment: ob.segments[0].seq}
+		}
+	}
+
+	return ob.saveCursor()
+}
+
+// SetOptions swaps the rotation and eviction limits, they apply from the next
+// append on.
+func (ob *Outbox) SetOptions(opts Options) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	ob.opts = opts
+}
+
+// Pending returns the number of bytes that have not been committed yet.
+func (ob *Outbox) Pending() int64 {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	var pending int64
+	for _, seg := range ob.segments {
+		switch {
+		case seg.seq > ob.cursor.Segment:
+			pending += seg.size
+		case seg.seq == ob.cursor.Segment:
+			pending += seg.size - ob.cursor.Offset
+		}
+	}
+
+	return pending
+}
+
+func (ob *Outbox) Close() error {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	if ob.active == nil {
+		return nil
+	}
+
+	err := ob.active.Close()
+	ob.active = nil
+
+	return err
+}
@@ -0,0 +1,93 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type breakerState int
+
+const (
+	stateClosed breakerState = iota
+	stateOpen
+	stateHalfOpen
+)
+
+// Breaker stops calls after Threshold consecutive failures and lets a single
+// trial call through once Cooldown has passed.
+type Breaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+	}
+}
+
+// Allow returns ErrCircuitOpen while calls should not be attempted.
+func (b *Breaker) Allow() error {
+	if b.Threshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if time.Since(b.openedAt) < b.Cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = stateHalfOpen
+		b.openedAt = time.Now()
+		return nil
+	case stateHalfOpen:
+		// a trial call is already in flight, let another one through if it
+		// never reported back
+		if time.Since(b.openedAt) < b.Cooldown {
+			return ErrCircuitOpen
+		}
+		b.openedAt = time.Now()
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = stateClosed
+	b.failures = 0
+}
+
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == stateHalfOpen || (b.Threshold > 0 && b.failures >= b.Threshold) {
+		b.state = stateOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// Open reports whether the breaker is currently rejecting calls.
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state == stateOpen && time.Since(b.openedAt) < b.Cooldown
+}
@@ -0,0 +1,136 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var DefaultRetryableCodes = []codes.Code{
+	codes.Unavailable,
+	codes.DeadlineExceeded,
+	codes.ResourceExhausted,
+	codes.Aborted,
+}
+
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// BackoffBase is the delay before the first retry, doubled on every retry
+	BackoffBase time.Duration
+	// BackoffCap is the upper bound for the delay between attempts
+	BackoffCap time.Duration
+	// Jitter is the fraction of the delay, between 0 and 1, that is randomized
+	Jitter         float64
+	RetryableCodes []codes.Code
+}
+
+// Backoff returns how long to wait before the given retry, starting at 1.
+func (p Policy) Backoff(retry int) time.Duration {
+	delay := p.BackoffBase
+	for i := 1; i < retry && (p.BackoffCap <= 0 || delay < p.BackoffCap); i++ {
+		delay *= 2
+	}
+	if p.BackoffCap > 0 && delay > p.BackoffCap {
+		delay = p.BackoffCap
+	}
+
+	if p.Jitter > 0 && delay > 0 {
+		spread := time.Duration(float64(delay) * min(p.Jitter, 1))
+		delay = delay - spread + time.Duration(rand.Int64N(int64(spread)+1))
+	}
+
+	return delay
+}
+
+// IsRetryable reports whether err carries one of the retryable gRPC codes.
+func (p Policy) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return slices.Contains(p.RetryableCodes, codes.DeadlineExceeded)
+	}
+
+	st, ok := status.FromError(unwrapStatus(err))
+	if !ok {
+		return false
+	}
+
+	return slices.Contains(p.RetryableCodes, st.Code())
+}
+
+// unwrapStatus digs the gRPC status out of errors built with errors.Join.
+func unwrapStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		return grpcErr.GRPCStatus().Err()
+	}
+
+	return err
+}
+
+// Do runs fn until it succeeds, fails with a non retryable error, runs out of
+// attempts or ctx is done.
+func (p Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	attempts := max(p.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			timer := time.NewTimer(p.Backoff(attempt - 1))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return errors.Join(ctx.Err(), err)
+			case <-timer.C:
+			}
+		}
+
+		err = fn(ctx)
+		if err == nil || !p.IsRetryable(err) {
+			return err
+		}
+	}
+
+	return errors.Join(fmt.Errorf("giving up after %d attempts", attempts), err)
+}
+
+// ParseCodes converts names such as "Unavailable" or "DEADLINE_EXCEEDED" into
+// gRPC codes.
+func ParseCodes(names []string) ([]codes.Code, error) {
+	known := make(map[string]codes.Code)
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		known[normalizeCode(c.String())] = c
+	}
+
+	var errs []error
+	parsed := make([]codes.Code, 0, len(names))
+	for _, name := range names {
+		c, ok := known[normalizeCode(name)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown grpc code %q", name))
+			continue
+		}
+
+		parsed = append(parsed, c)
+	}
+
+	return parsed, errors.Join(errs...)
+}
+
+func normalizeCode(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", ""))
+}
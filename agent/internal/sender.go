@@ -8,64 +8,129 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"os"
+	"sync"
 	"time"
 
 	"github.com/microwatcher/agent/internal/config"
+	"github.com/microwatcher/agent/internal/outbox"
+	"github.com/microwatcher/agent/internal/retry"
 	v1 "github.com/microwatcher/shared/pkg/gen/microwatcher/v1"
-	"github.com/microwatcher/shared/pkg/logger"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const attemptTimeout = time.Second * 2
+
+type endpoint struct {
+	addr    string
+	conn    *grpc.ClientConn
+	client  v1.TelemetryServiceClient
+	breaker *retry.Breaker
+}
+
 type IngestClient struct {
-	client       v1.TelemetryServiceClient
-	conn         *grpc.ClientConn
+	endpoints    []*endpoint
+	strategy     string
+	retryPolicy  retry.Policy
+	mu           sync.Mutex
+	current      int
 	Logger       *slog.Logger
 	ClientID     string
 	ClientSecret []byte
 }
 
-func NewIngestClient(addr string, cfg *config.Config) *IngestClient {
-	defaultLogger := logger.NewDefaultLogger()
-	conn, err := grpc.NewClient(
-		addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+func NewIngestClient(cfg *config.Config) (*IngestClient, error) {
+	creds, err := transportCredentials(cfg.TLS)
 	if err != nil {
-		defaultLogger.Error("failed to connect to ingest", slog.String("error", err.Error()))
-		os.Exit(1)
+		return nil, errors.Join(errors.New("failed to set up tls"), err)
 	}
 
-	client := v1.NewTelemetryServiceClient(conn)
+	endpoints := make([]*endpoint, len(cfg.IngestAddrs))
+	for i, addr := range cfg.IngestAddrs {
+		conn, err := grpc.NewClient(
+			addr,
+			grpc.WithTransportCredentials(creds),
+		)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to connect to ingest %s", addr), err)
+		}
+
+		endpoints[i] = &endpoint{
+			addr:    addr,
+			conn:    conn,
+			client:  v1.NewTelemetryServiceClient(conn),
+			breaker: retry.NewBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		}
+	}
 
 	return &IngestClient{
-		Logger:       defaultLogger,
-		client:       client,
-		conn:         conn,
+		Logger:       cfg.Logger,
+		endpoints:    endpoints,
+		strategy:     cfg.IngestStrategy,
+		retryPolicy:  cfg.Retry,
 		ClientID:     cfg.ClientID,
 		ClientSecret: cfg.ClientSecret,
+	}, nil
+}
+
+func (ic *IngestClient) Close() error {
+	var errs []error
+	for _, ep := range ic.endpoints {
+		errs = append(errs, ep.conn.Close())
 	}
+	return errors.Join(errs...)
 }
 
-func (ic *IngestClient) SendData(telemetries []*v1.Telemetry) error {
-	ctx, cancel := context.WithTimeout(
-		context.Background(),
-		time.Second*2,
-	)
-	defer cancel()
+// pick returns the endpoint for the next attempt, skipping the ones whose
+// breaker is open. With failover the client sticks to the last endpoint that
+// worked, with round-robin every call moves on to the next one.
+func (ic *IngestClient) pick(rotate bool) (*endpoint, error) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
 
-	req := &v1.SendTelemetryRequest{
-		Telemetries: telemetries,
+	if rotate && ic.strategy == config.StrategyRoundRobin {
+		ic.current = (ic.current + 1) % len(ic.endpoints)
+	}
+
+	for i := range ic.endpoints {
+		idx := (ic.current + i) % len(ic.endpoints)
+		if err := ic.endpoints[idx].breaker.Allow(); err != nil {
+			continue
+		}
+
+		if idx != ic.current {
+			ic.Logger.Info("switching ingest server",
+				slog.String("from", ic.endpoints[ic.current].addr),
+				slog.String("to", ic.endpoints[idx].addr),
+			)
+			ic.current = idx
+		}
+
+		return ic.endpoints[idx], nil
+	}
+
+	return nil, retry.ErrCircuitOpen
+}
+
+// failover moves away from ep after it failed, unless another call already
+// did.
+func (ic *IngestClient) failover(ep *endpoint) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	if len(ic.endpoints) > 1 && ic.endpoints[ic.current] == ep {
+		ic.current = (ic.current + 1) % len(ic.endpoints)
 	}
+}
 
-	payloadBytes, err := proto.Marshal(req)
+// signedContext attaches the client id and the HMAC of msg to ctx.
+func (ic *IngestClient) signedContext(ctx context.Context, msg proto.Message) (context.Context, error) {
+	payloadBytes, err := proto.Marshal(msg)
 	if err != nil {
-		return fmt.Errorf("failed to marshal telemetry: %w", err)
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
 	mac := hmac.New(sha256.New, ic.ClientSecret)
@@ -78,12 +143,135 @@ func (ic *IngestClient) SendData(telemetries []*v1.Telemetry) error {
 		"x-client-id": ic.ClientID,
 	})
 
-	response, err := ic.client.SendTelemetry(
-		metadata.NewOutgoingContext(ctx, md),
-		req,
-	)
+	return metadata.NewOutgoingContext(ctx, md), nil
+}
+
+// call runs fn with the retry policy, each attempt with its own timeout and
+// against the endpoint picked for it. Endpoints whose circuit breaker is open
+// are skipped until their cooldown passes.
+func (ic *IngestClient) call(ctx context.Context, method string, fn func(ctx context.Context, client v1.TelemetryServiceClient) error) error {
+	attempt := 0
+	return ic.retryPolicy.Do(ctx, func(ctx context.Context) error {
+		attempt++
+
+		ep, err := ic.pick(attempt == 1)
+		if err != nil {
+			return err
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, attemptTimeout)
+		defer cancel()
+
+		err = fn(attemptCtx, ep.client)
+
+		// only failures caused by ingest being unreachable or overloaded trip
+		// the breaker, a rejected payload still means the server is healthy
+		switch {
+		case errors.Is(err, context.Canceled):
+		case err != nil && ic.retryPolicy.IsRetryable(err):
+			ic.Logger.Warn("ingest call failed",
+				slog.String("method", method),
+				slog.String("addr", ep.addr),
+				slog.Int("attempt", attempt),
+				slog.String("error", err.Error()),
+			)
+
+			ep.breaker.Failure()
+			if ep.breaker.Open() {
+				ic.Logger.Warn("circuit breaker opened, pausing calls to ingest server",
+					slog.String("addr", ep.addr),
+				)
+			}
+			ic.failover(ep)
+		default:
+			ep.breaker.Success()
+		}
+
+		return err
+	})
+}
+
+const sendBatchSize = 100
+
+// drain sends the outbox oldest first, up to batchSize records at a time,
+// committing each batch once ingest accepted it. It stops at the first
+// failed batch so it is retried, in order, on the next call.
+func drain[T proto.Message](ctx context.Context, logger *slog.Logger, queue *outbox.Outbox, batchSize int, newRecord func() T, send func(ctx context.Context, batch []T) error) error {
+	for ctx.Err() == nil {
+		records, pos, err := queue.Peek(batchSize)
+		if err != nil {
+			return errors.Join(errors.New("failed to read outbox"), err)
+		}
+
+		if len(records) == 0 {
+			return nil
+		}
+
+		batch := make([]T, 0, len(records))
+		for _, record := range records {
+			msg := newRecord()
+			if err := proto.Unmarshal(record, msg); err != nil {
+				logger.Error("dropping unreadable record from outbox",
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+
+			batch = append(batch, msg)
+		}
+
+		if len(batch) > 0 {
+			if err := send(ctx, batch); err != nil {
+				return err
+			}
+		}
+
+		if err := queue.Commit(pos); err != nil {
+			return errors.Join(errors.New("failed to commit outbox"), err)
+		}
+	}
+
+	return ctx.Err()
+}
+
+// perRecord drains an outbox whose records are each sent as their own
+// request, committing every record once ingest accepted it so a failure
+// doesn't send the ones before it again.
+func perRecord[T proto.Message](ctx context.Context, logger *slog.Logger, queue *outbox.Outbox, newRecord func() T, send func(ctx context.Context, req T) error) error {
+	return drain(ctx, logger, queue, 1, newRecord, func(ctx context.Context, batch []T) error {
+		for _, req := range batch {
+			if err := send(ctx, req); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// SendData drains the telemetry outbox.
+func (ic *IngestClient) SendData(ctx context.Context, queue *outbox.Outbox) error {
+	return drain(ctx, ic.Logger, queue, sendBatchSize, func() *v1.Telemetry { return &v1.Telemetry{} }, ic.sendTelemetries)
+}
+
+// SendMetrics drains the custom metrics outbox, every record is the request
+// of a single tick.
+func (ic *IngestClient) SendMetrics(ctx context.Context, queue *outbox.Outbox) error {
+	return perRecord(ctx, ic.Logger, queue, func() *v1.SendMetricsRequest { return &v1.SendMetricsRequest{} }, ic.sendMetrics)
+}
+
+func (ic *IngestClient) sendMetrics(ctx context.Context, req *v1.SendMetricsRequest) error {
+	signedCtx, err := ic.signedContext(ctx, req)
 	if err != nil {
-		return errors.Join(fmt.Errorf("failed to send data"), err)
+		return err
+	}
+
+	var response *v1.SendMetricsResponse
+	if err := ic.call(signedCtx, "SendMetrics", func(ctx context.Context, client v1.TelemetryServiceClient) error {
+		response, err = client.SendMetrics(ctx, req)
+		return err
+	}); err != nil {
+		return errors.Join(fmt.Errorf("failed to send metrics"), err)
 	}
 
 	if !response.Success {
@@ -93,67 +281,121 @@ func (ic *IngestClient) SendData(telemetries []*v1.Telemetry) error {
 	return nil
 }
 
-func (ic *IngestClient) HealthCheck(ctx context.Context, identifier string) error {
-	ctx, cancel := context.WithTimeout(
-		ctx,
-		time.Second*2,
-	)
-	defer cancel()
+// SendLogs drains the logs outbox, every record is the request of a single
+// tick.
+func (ic *IngestClient) SendLogs(ctx context.Context, queue *outbox.Outbox) error {
+	return perRecord(ctx, ic.Logger, queue, func() *v1.SendLogsRequest { return &v1.SendLogsRequest{} }, ic.sendLogs)
+}
 
-	req := &v1.HealthCheckRequest{
-		Timestamp:  timestamppb.Now(),
-		Identifier: identifier,
+func (ic *IngestClient) sendLogs(ctx context.Context, req *v1.SendLogsRequest) error {
+	signedCtx, err := ic.signedContext(ctx, req)
+	if err != nil {
+		return err
 	}
 
-	payloadBytes, err := proto.Marshal(req)
-	if err != nil {
-		return fmt.Errorf("failed to marshal telemetry: %w", err)
+	var response *v1.SendLogsResponse
+	if err := ic.call(signedCtx, "SendLogs", func(ctx context.Context, client v1.TelemetryServiceClient) error {
+		response, err = client.SendLogs(ctx, req)
+		return err
+	}); err != nil {
+		return errors.Join(fmt.Errorf("failed to send logs"), err)
 	}
 
-	mac := hmac.New(sha256.New, ic.ClientSecret)
-	mac.Write(payloadBytes)
-	signature := mac.Sum(nil)
-	signatureHex := hex.EncodeToString(signature)
+	if !response.Success {
+		return errors.New("response was not successful")
+	}
 
-	md := metadata.New(map[string]string{
-		"x-signature": signatureHex,
-		"x-client-id": ic.ClientID,
-	})
+	return nil
+}
 
-	_, err = ic.client.HealthCheck(metadata.NewOutgoingContext(ctx, md), req)
+// SendProbeResults drains the probes outbox, every record is the request of
+// a single tick.
+func (ic *IngestClient) SendProbeResults(ctx context.Context, queue *outbox.Outbox) error {
+	return perRecord(ctx, ic.Logger, queue, func() *v1.SendProbeResultsRequest { return &v1.SendProbeResultsRequest{} }, ic.sendProbeResults)
+}
+
+func (ic *IngestClient) sendProbeResults(ctx context.Context, req *v1.SendProbeResultsRequest) error {
+	signedCtx, err := ic.signedContext(ctx, req)
 	if err != nil {
-		return errors.Join(fmt.Errorf("failed to HealthCheck"), err)
+		return err
+	}
+
+	var response *v1.SendProbeResultsResponse
+	if err := ic.call(signedCtx, "SendProbeResults", func(ctx context.Context, client v1.TelemetryServiceClient) error {
+		response, err = client.SendProbeResults(ctx, req)
+		return err
+	}); err != nil {
+		return errors.Join(fmt.Errorf("failed to send probe results"), err)
+	}
+
+	if !response.Success {
+		return errors.New("response was not successful")
 	}
 
 	return nil
 }
 
-func (ic *IngestClient) Ping(ctx context.Context) error {
-	ctx, cancel := context.WithTimeout(
-		ctx,
-		time.Second*2,
-	)
-	defer cancel()
+func (ic *IngestClient) sendTelemetries(ctx context.Context, telemetries []*v1.Telemetry) error {
+	req := &v1.SendTelemetryRequest{
+		Telemetries: telemetries,
+	}
 
-	req := &v1.PingRequest{}
+	signedCtx, err := ic.signedContext(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	var response *v1.SendTelemetryResponse
+	if err := ic.call(signedCtx, "SendTelemetry", func(ctx context.Context, client v1.TelemetryServiceClient) error {
+		response, err = client.SendTelemetry(ctx, req)
+		return err
+	}); err != nil {
+		return errors.Join(fmt.Errorf("failed to send data"), err)
+	}
 
-	payloadBytes, err := proto.Marshal(req)
+	if !response.Success {
+		return errors.New("response was not successful")
+	}
+
+	return nil
+}
+
+// HealthCheck reports the agent is alive, inventory is only sent when it
+// changed and is nil otherwise.
+func (ic *IngestClient) HealthCheck(ctx context.Context, identifier string, inventory *v1.DeviceInventory) error {
+	req := &v1.HealthCheckRequest{
+		Timestamp:  timestamppb.Now(),
+		Identifier: identifier,
+		Inventory:  inventory,
+	}
+
+	signedCtx, err := ic.signedContext(ctx, req)
 	if err != nil {
-		return fmt.Errorf("failed to marshal telemetry: %w", err)
+		return err
 	}
 
-	mac := hmac.New(sha256.New, ic.ClientSecret)
-	mac.Write(payloadBytes)
-	signature := mac.Sum(nil)
-	signatureHex := hex.EncodeToString(signature)
+	if err := ic.call(signedCtx, "HealthCheck", func(ctx context.Context, client v1.TelemetryServiceClient) error {
+		_, err := client.HealthCheck(ctx, req)
+		return err
+	}); err != nil {
+		return errors.Join(fmt.Errorf("failed to HealthCheck"), err)
+	}
 
-	md := metadata.New(map[string]string{
-		"x-signature": signatureHex,
-		"x-client-id": ic.ClientID,
-	})
+	return nil
+}
 
-	_, err = ic.client.Ping(metadata.NewOutgoingContext(ctx, md), req)
+func (ic *IngestClient) Ping(ctx context.Context) error {
+	req := &v1.PingRequest{}
+
+	signedCtx, err := ic.signedContext(ctx, req)
 	if err != nil {
+		return err
+	}
+
+	if err := ic.call(signedCtx, "Ping", func(ctx context.Context, client v1.TelemetryServiceClient) error {
+		_, err := client.Ping(ctx, req)
+		return err
+	}); err != nil {
 		return errors.Join(fmt.Errorf("failed to ping"), err)
 	}
 
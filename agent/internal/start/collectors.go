@@ -0,0 +1,98 @@
+package start
+
+import (
+	"path/filepath"
+
+	"github.com/microwatcher/agent/internal/config"
+	"github.com/microwatcher/agent/internal/systeminformation"
+)
+
+// newRegistry registers every enabled collector with its configured
+// interval and timeout.
+func newRegistry(cfg *config.Config) *systeminformation.Registry {
+	registry := systeminformation.NewRegistry()
+
+	schedule := func(name string) systeminformation.Schedule {
+		settings := cfg.Collectors[name]
+		return systeminformation.NewSchedule(settings.Interval, settings.Timeout)
+	}
+
+	collectors := []systeminformation.Collector{
+		systeminformation.NewCPUCollector(schedule("cpu"), cfg.PerCoreCPU, cfg.InContainer),
+		systeminformation.NewMemoryCollector(schedule("memory"), cfg.InContainer),
+		systeminformation.NewDiskCollector(schedule("disk"), cfg.DiskFilter),
+		systeminformation.NewNetworkCollector(schedule("network")),
+		systeminformation.NewSystemCollector(schedule("system")),
+		systeminformation.NewProcessCollector(schedule("process"), cfg.ProcessTop),
+		systeminformation.NewSensorsCollector(schedule("sensors")),
+		systeminformation.NewCgroupCollector(schedule("cgroup")),
+		systeminformation.NewSystemdCollector(schedule("systemd"), cfg.SystemdUnits, cfg.JournalPriority,
+			filepath.Join(cfg.OutboxDir, "log-offsets", "journal.cursor"),
+		),
+		systeminformation.NewCertificateCollector(schedule("certificates"), cfg.CertificatePaths, cfg.CertificateEndpoints),
+	}
+
+	for _, collector := range collectors {
+		if cfg.CollectorEnabled(collector.Name()) {
+			registry.Register(collector)
+		}
+	}
+
+	for _, exec := range cfg.ExecCollectors {
+		if !exec.Enabled {
+			continue
+		}
+
+		registry.Register(systeminformation.NewExecCollector(
+			systeminformation.NewSchedule(exec.Interval, exec.Timeout),
+			exec.Name,
+			exec.Command,
+			exec.Format,
+		))
+	}
+
+	for _, scrape := range cfg.ScrapeCollectors {
+		if !scrape.Enabled {
+			continue
+		}
+
+		registry.Register(systeminformation.NewScrapeCollector(
+			systeminformation.NewSchedule(scrape.Interval, scrape.Timeout),
+			scrape.Name,
+			scrape.URL,
+			scrape.Filter,
+			scrape.Relabel,
+		))
+	}
+
+	for _, logs := range cfg.LogCollectors {
+		if !logs.Enabled {
+			continue
+		}
+
+		registry.Register(systeminformation.NewLogCollector(
+			systeminformation.NewSchedule(logs.Interval, logs.Timeout),
+			logs.Name,
+			logs.Paths,
+			logs.Parser,
+			logs.FromBeginning,
+			filepath.Join(cfg.OutboxDir, "log-offsets", logs.Name+".json"),
+		))
+	}
+
+	for _, probe := range cfg.ProbeCollectors {
+		if !probe.Enabled {
+			continue
+		}
+
+		registry.Register(systeminformation.NewProbeCollector(
+			systeminformation.NewSchedule(probe.Interval, probe.Timeout),
+			probe.Name,
+			probe.Kind,
+			probe.Target,
+			probe.Assertions,
+		))
+	}
+
+	return registry
+}
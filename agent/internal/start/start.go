@@ -3,18 +3,25 @@ package start
 import (
 	"context"
 	"log/slog"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/microwatcher/agent/internal"
 	"github.com/microwatcher/agent/internal/config"
+	"github.com/microwatcher/agent/internal/outbox"
 	"github.com/microwatcher/agent/internal/systeminformation"
 	v1 "github.com/microwatcher/shared/pkg/gen/microwatcher/v1"
-	"github.com/microwatcher/shared/pkg/iter"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/proto"
 )
 
 func Ping(ctx context.Context, config *config.Config) {
-	client := internal.NewIngestClient("localhost:50051", config)
+	client, err := internal.NewIngestClient(config)
+	if err != nil {
+		config.Logger.Error("failed to create ingest client", slog.String("error", err.Error()))
+		return
+	}
+	defer client.Close()
 
 	if err := client.Ping(ctx); err != nil {
 		config.Logger.Error("failed to ping", slog.String("error", err.Error()))
@@ -24,80 +31,298 @@ func Ping(ctx context.Context, config *config.Config) {
 	config.Logger.Info("ping successful")
 }
 
-func Start(ctx context.Context, config *config.Config) {
-	aliveTicker := time.NewTicker(time.Second * 5)
+// inventoryRefresh is how often the inventory is read again, it only
+// changes on upgrades, reboots or network changes.
+const inventoryRefresh = 10 * time.Minute
+
+// state holds what a config reload swaps out while the agent keeps running.
+type state struct {
+	mu       sync.RWMutex
+	config   *config.Config
+	client   *internal.IngestClient
+	registry *systeminformation.Registry
+}
+
+func (rt *state) get() (*config.Config, *internal.IngestClient, *systeminformation.Registry) {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
+	return rt.config, rt.client, rt.registry
+}
+
+// swap installs a new config, ingest client and collectors, calls still
+// running against the old client fail and their data stays in the outbox.
+// The new collectors start without previous samples, so their first rates
+// are missing.
+func (rt *state) swap(cfg *config.Config) error {
+	client, err := internal.NewIngestClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	rt.mu.Lock()
+	old := rt.client
+	oldRegistry := rt.registry
+	rt.config = cfg
+	rt.client = client
+	rt.registry = newRegistry(cfg)
+	rt.mu.Unlock()
+
+	if err := old.Close(); err != nil {
+		cfg.Logger.Warn("failed to close previous ingest client", slog.String("error", err.Error()))
+	}
+	if err := oldRegistry.Close(); err != nil {
+		cfg.Logger.Warn("failed to close previous collectors", slog.String("error", err.Error()))
+	}
+
+	return nil
+}
+
+// Start runs the agent until ctx is done, every config received on reloads
+// replaces the current one without dropping buffered telemetry.
+func Start(ctx context.Context, config *config.Config, reloads <-chan *config.Config) {
+	aliveTicker := time.NewTicker(config.HealthCheckInterval)
 	processTicker := time.NewTicker(config.MetricInterval)
 
-	client := internal.NewIngestClient("localhost:50051", config)
+	queue, err := outbox.Open(config.OutboxDir, config.Outbox, config.Logger)
+	if err != nil {
+		config.Logger.Error("failed to open outbox",
+			slog.String("dir", config.OutboxDir),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	defer queue.Close()
+
+	// custom metrics, logs and probe results get their own outboxes so a
+	// backlog of them can't hold back telemetry, the telemetry outbox ignores
+	// subdirectories
+	metricsDir := filepath.Join(config.OutboxDir, "metrics")
+	metricsQueue, err := outbox.Open(metricsDir, config.Outbox, config.Logger)
+	if err != nil {
+		config.Logger.Error("failed to open outbox",
+			slog.String("dir", metricsDir),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	defer metricsQueue.Close()
+
+	logsDir := filepath.Join(config.OutboxDir, "logs")
+	logsQueue, err := outbox.Open(logsDir, config.Outbox, config.Logger)
+	if err != nil {
+		config.Logger.Error("failed to open outbox",
+			slog.String("dir", logsDir),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	defer logsQueue.Close()
+
+	probesDir := filepath.Join(config.OutboxDir, "probes")
+	probesQueue, err := outbox.Open(probesDir, config.Outbox, config.Logger)
+	if err != nil {
+		config.Logger.Error("failed to open outbox",
+			slog.String("dir", probesDir),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	defer probesQueue.Close()
+
+	client, err := internal.NewIngestClient(config)
+	if err != nil {
+		config.Logger.Error("failed to create ingest client", slog.String("error", err.Error()))
+		return
+	}
+
+	rt := &state{
+		config:   config,
+		client:   client,
+		registry: newRegistry(config),
+	}
+	defer func() {
+		_, client, _ := rt.get()
+		client.Close()
+	}()
 
 	defer aliveTicker.Stop()
 	defer processTicker.Stop()
 
 	go func() {
+		// the inventory goes along the first health check and then only
+		// when it changed, until ingest stored it it's sent every time
+		var current, sentInventory *v1.DeviceInventory
+		var readAt time.Time
+		var readErr string
+		healthCheck := func() {
+			config, client, _ := rt.get()
+
+			if time.Since(readAt) >= inventoryRefresh {
+				read, err := systeminformation.ReadInventory(ctx)
+				// the same part usually fails on every read
+				if err != nil && err.Error() != readErr {
+					config.Logger.Warn("failed to read part of the inventory", slog.String("error", err.Error()))
+				}
+				readErr = ""
+				if err != nil {
+					readErr = err.Error()
+				}
+
+				current = toInventory(read)
+				readAt = time.Now()
+			}
+
+			inventory := current
+			if proto.Equal(inventory, sentInventory) {
+				inventory = nil
+			}
+
+			if err := client.HealthCheck(ctx, config.Identifier, inventory); err != nil {
+				config.Logger.Error("failed to health check", slog.String("error", err.Error()))
+				return
+			}
+
+			if inventory != nil {
+				sentInventory = inventory
+				config.Logger.Info("inventory reported")
+			}
+		}
+
+		healthCheck()
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case <-aliveTicker.C:
-				if err := client.HealthCheck(ctx, config.Identifier); err != nil {
-					config.Logger.Error("failed to health check", slog.String("error", err.Error()))
-					continue
-				}
+				healthCheck()
 			}
 		}
 	}()
 
 	go func() {
-		var telemetries []*v1.Telemetry
-
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case <-processTicker.C:
-				runInfo := systeminformation.GetSystemInformation()
-
-				telemetryDisks := iter.Map(runInfo.Disks, func(disk systeminformation.SystemInformationDisk) *v1.TelemetryDisk {
-					return &v1.TelemetryDisk{
-						Label:      disk.Label,
-						Mountpoint: disk.Mountpoint,
-						Total:      disk.Total,
-						Used:       disk.Used,
-						Free:       disk.Free,
-					}
-				})
+				config, client, registry := rt.get()
 
-				telemetryNetworks := iter.Map(runInfo.Networks, func(network systeminformation.SystemInformationNetwork) *v1.TelemetryNetwork {
-					return &v1.TelemetryNetwork{
-						Name:      network.Name,
-						BytesSent: network.BytesSent,
-						BytesRecv: network.BytesRecv,
-					}
-				})
-
-				telemetries = append(telemetries, &v1.Telemetry{
-					Timestamp:   timestamppb.Now(),
-					Identifier:  config.Identifier,
-					TotalMemory: runInfo.TotalMemory,
-					FreeMemory:  runInfo.FreeMemory,
-					UsedMemory:  runInfo.UsedMemory,
-					TotalCpu:    runInfo.TotalCPU,
-					FreeCpu:     runInfo.FreeCPU,
-					UsedCpu:     runInfo.UsedCPU,
-					Disks:       telemetryDisks,
-					Networks:    telemetryNetworks,
-				})
-
-				if err := client.SendData(telemetries); err != nil {
-					config.Logger.Error("failed to send data", slog.String("error", err.Error()))
+				runInfo := registry.Collect(ctx)
+				for _, collectorErr := range runInfo.Errors {
+					config.Logger.Warn("collector failed",
+						slog.String("collector", collectorErr.Collector),
+						slog.String("error", collectorErr.Err.Error()),
+					)
+				}
+
+				payload, err := proto.Marshal(toTelemetry(config.Identifier, runInfo))
+				if err != nil {
+					config.Logger.Error("failed to marshal telemetry", slog.String("error", err.Error()))
 					continue
 				}
 
-				telemetries = make([]*v1.Telemetry, 0)
+				if err := queue.Append(payload); err != nil {
+					config.Logger.Error("failed to buffer telemetry", slog.String("error", err.Error()))
+				}
+
+				if len(runInfo.Metrics) > 0 {
+					buffer(config, metricsQueue, "metrics", toMetricsRequest(config.Identifier, runInfo))
+				}
+				if err := client.SendMetrics(ctx, metricsQueue); err != nil {
+					config.Logger.Error("failed to send metrics",
+						slog.String("error", err.Error()),
+						slog.Int64("pending bytes", metricsQueue.Pending()),
+					)
+				}
+
+				logsBuffered := true
+				for _, req := range toLogsRequests(config.Identifier, runInfo) {
+					logsBuffered = buffer(config, logsQueue, "logs", req) && logsBuffered
+				}
+				// the collectors move past what they read only once it's in
+				// the outbox, otherwise they read it again next tick
+				if logsBuffered {
+					if err := runInfo.Commit(); err != nil {
+						config.Logger.Error("failed to commit collected logs", slog.String("error", err.Error()))
+					}
+				}
+				if err := client.SendLogs(ctx, logsQueue); err != nil {
+					config.Logger.Error("failed to send logs",
+						slog.String("error", err.Error()),
+						slog.Int64("pending bytes", logsQueue.Pending()),
+					)
+				}
+
+				if len(runInfo.Probes) > 0 {
+					buffer(config, probesQueue, "probe results", toProbeResultsRequest(config.Identifier, runInfo))
+				}
+				if err := client.SendProbeResults(ctx, probesQueue); err != nil {
+					config.Logger.Error("failed to send probe results",
+						slog.String("error", err.Error()),
+						slog.Int64("pending bytes", probesQueue.Pending()),
+					)
+				}
+
+				if err := client.SendData(ctx, queue); err != nil {
+					config.Logger.Error("failed to send data",
+						slog.String("error", err.Error()),
+						slog.Int64("pending bytes", queue.Pending()),
+					)
+					continue
+				}
 			}
 		}
 	}()
 
-	<-ctx.Done()
-	config.Logger.Info("done running")
+	for {
+		select {
+		case <-ctx.Done():
+			config.Logger.Info("done running")
+			return
+		case newConfig := <-reloads:
+			// before the swap, the new collectors keep their state there
+			if newConfig.OutboxDir != config.OutboxDir {
+				newConfig.Logger.Warn("outbox dir can't change while running, restart the agent to apply it",
+					slog.String("current", config.OutboxDir),
+					slog.String("new", newConfig.OutboxDir),
+				)
+				newConfig.OutboxDir = config.OutboxDir
+			}
+
+			if err := rt.swap(newConfig); err != nil {
+				newConfig.Logger.Error("failed to apply reloaded config, keeping the current one",
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+
+			queue.SetOptions(newConfig.Outbox)
+			metricsQueue.SetOptions(newConfig.Outbox)
+			logsQueue.SetOptions(newConfig.Outbox)
+			probesQueue.SetOptions(newConfig.Outbox)
+			aliveTicker.Reset(newConfig.HealthCheckInterval)
+			processTicker.Reset(newConfig.MetricInterval)
+			config = newConfig
+
+			config.Logger.Info("config reloaded")
+		}
+	}
+}
+
+// buffer appends the request of a tick to queue, what names it in logs. It
+// reports whether the request was appended.
+func buffer(config *config.Config, queue *outbox.Outbox, what string, req proto.Message) bool {
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		config.Logger.Error("failed to marshal "+what, slog.String("error", err.Error()))
+		return false
+	}
+
+	if err := queue.Append(payload); err != nil {
+		config.Logger.Error("failed to buffer "+what, slog.String("error", err.Error()))
+		return false
+	}
+
+	return true
 }
@@ -0,0 +1,313 @@
+package start
+
+import (
+	"time"
+
+	"github.com/microwatcher/agent/internal/metrics"
+	"github.com/microwatcher/agent/internal/systeminformation"
+	v1 "github.com/microwatcher/shared/pkg/gen/microwatcher/v1"
+	"github.com/microwatcher/shared/pkg/iter"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toTelemetry maps the collected information onto what is sent to ingest.
+func toTelemetry(identifier string, info systeminformation.SystemInformation) *v1.Telemetry {
+	telemetryCPUs := iter.Map(info.CPUs, func(cpu systeminformation.SystemInformationCPU) *v1.TelemetryCPU {
+		return &v1.TelemetryCPU{
+			Name:   cpu.Name,
+			User:   cpu.User,
+			System: cpu.System,
+			Iowait: cpu.Iowait,
+			Steal:  cpu.Steal,
+			Idle:   cpu.Idle,
+		}
+	})
+
+	telemetryDisks := iter.Map(info.Disks, func(disk systeminformation.SystemInformationDisk) *v1.TelemetryDisk {
+		return &v1.TelemetryDisk{
+			Label:       disk.Label,
+			Mountpoint:  disk.Mountpoint,
+			Fstype:      disk.FSType,
+			Total:       disk.Total,
+			Used:        disk.Used,
+			Free:        disk.Free,
+			InodesTotal: disk.InodesTotal,
+			InodesUsed:  disk.InodesUsed,
+			InodesFree:  disk.InodesFree,
+		}
+	})
+
+	telemetryDiskIO := iter.Map(info.DiskIO, func(diskIO systeminformation.SystemInformationDiskIO) *v1.TelemetryDiskIO {
+		return &v1.TelemetryDiskIO{
+			Name:             diskIO.Name,
+			ReadBytes:        diskIO.ReadBytes,
+			WriteBytes:       diskIO.WriteBytes,
+			ReadCount:        diskIO.ReadCount,
+			WriteCount:       diskIO.WriteCount,
+			IoTime:           diskIO.IoTime,
+			WeightedIoTime:   diskIO.WeightedIoTime,
+			IopsInProgress:   diskIO.IopsInProgress,
+			ReadBytesPerSec:  diskIO.ReadBytesPerSec,
+			WriteBytesPerSec: diskIO.WriteBytesPerSec,
+			ReadsPerSec:      diskIO.ReadsPerSec,
+			WritesPerSec:     diskIO.WritesPerSec,
+			Utilisation:      diskIO.Utilisation,
+			QueueSize:        diskIO.QueueSize,
+		}
+	})
+
+	telemetryNetworks := iter.Map(info.Networks, func(network systeminformation.SystemInformationNetwork) *v1.TelemetryNetwork {
+		return &v1.TelemetryNetwork{
+			Name:              network.Name,
+			BytesSent:         network.BytesSent,
+			BytesRecv:         network.BytesRecv,
+			PacketsSent:       network.PacketsSent,
+			PacketsRecv:       network.PacketsRecv,
+			ErrorsIn:          network.ErrorsIn,
+			ErrorsOut:         network.ErrorsOut,
+			DropsIn:           network.DropsIn,
+			DropsOut:          network.DropsOut,
+			BytesSentPerSec:   network.BytesSentPerSec,
+			BytesRecvPerSec:   network.BytesRecvPerSec,
+			PacketsSentPerSec: network.PacketsSentPerSec,
+			PacketsRecvPerSec: network.PacketsRecvPerSec,
+		}
+	})
+
+	telemetryProcesses := iter.Map(info.Processes, func(proc systeminformation.SystemInformationProcess) *v1.TelemetryProcess {
+		return &v1.TelemetryProcess{
+			Pid:         proc.PID,
+			Name:        proc.Name,
+			CmdlineHash: proc.CmdlineHash,
+			User:        proc.User,
+			CpuPercent:  proc.CPUPercent,
+			Rss:         proc.RSS,
+			Threads:     proc.Threads,
+			OpenFds:     proc.OpenFDs,
+		}
+	})
+
+	telemetrySensors := iter.Map(info.Sensors, func(sensor systeminformation.SystemInformationSensor) *v1.TelemetrySensor {
+		return &v1.TelemetrySensor{
+			Kind:     sensor.Kind,
+			Chip:     sensor.Chip,
+			Label:    sensor.Label,
+			Value:    sensor.Value,
+			High:     sensor.High,
+			Critical: sensor.Critical,
+		}
+	})
+
+	telemetryCgroups := iter.Map(info.Cgroups, func(cgroup systeminformation.SystemInformationCgroup) *v1.TelemetryCgroup {
+		return &v1.TelemetryCgroup{
+			Path:               cgroup.Path,
+			ContainerId:        cgroup.ContainerID,
+			CpuUsageUsec:       cgroup.CPUUsageUsec,
+			CpuPercent:         cgroup.CPUPercent,
+			ThrottledPeriods:   cgroup.ThrottledPeriods,
+			MemoryCurrent:      cgroup.MemoryCurrent,
+			MemoryMax:          cgroup.MemoryMax,
+			PidsCurrent:        cgroup.PidsCurrent,
+			PidsMax:            cgroup.PidsMax,
+			IoReadBytes:        cgroup.IOReadBytes,
+			IoWriteBytes:       cgroup.IOWriteBytes,
+			IoReadBytesPerSec:  cgroup.IOReadBytesPerSec,
+			IoWriteBytesPerSec: cgroup.IOWriteBytesPerSec,
+		}
+	})
+
+	telemetryUnits := iter.Map(info.Units, func(unit systeminformation.SystemInformationUnit) *v1.TelemetryUnit {
+		return &v1.TelemetryUnit{
+			Name:        unit.Name,
+			LoadState:   unit.LoadState,
+			ActiveState: unit.ActiveState,
+			SubState:    unit.SubState,
+			Restarts:    unit.Restarts,
+			MainPid:     unit.MainPID,
+		}
+	})
+
+	telemetryCertificates := iter.Map(info.Certificates, func(cert systeminformation.SystemInformationCertificate) *v1.TelemetryCertificate {
+		return &v1.TelemetryCertificate{
+			Source:        cert.Source,
+			Location:      cert.Location,
+			Fingerprint:   cert.Fingerprint,
+			Subject:       cert.Subject,
+			Issuer:        cert.Issuer,
+			Sans:          cert.SANs,
+			NotBefore:     timestamppb.New(cert.NotBefore),
+			NotAfter:      timestamppb.New(cert.NotAfter),
+			DaysRemaining: cert.DaysRemaining,
+		}
+	})
+
+	telemetryErrors := iter.Map(info.Errors, func(collectorErr systeminformation.CollectorError) *v1.TelemetryCollectorError {
+		return &v1.TelemetryCollectorError{
+			Collector: collectorErr.Collector,
+			Error:     collectorErr.Err.Error(),
+		}
+	})
+
+	var telemetrySystem *v1.TelemetrySystem
+	if info.System != nil {
+		telemetrySystem = &v1.TelemetrySystem{
+			Load1:        info.System.Load1,
+			Load5:        info.System.Load5,
+			Load15:       info.System.Load15,
+			Uptime:       info.System.Uptime,
+			ProcsTotal:   info.System.ProcsTotal,
+			ProcsRunning: info.System.ProcsRunning,
+			ProcsBlocked: info.System.ProcsBlocked,
+		}
+	}
+
+	return &v1.Telemetry{
+		Timestamp:       timestamppb.New(info.Timestamp),
+		Identifier:      identifier,
+		TotalMemory:     info.TotalMemory,
+		FreeMemory:      info.FreeMemory,
+		UsedMemory:      info.UsedMemory,
+		BuffersMemory:   info.Buffers,
+		CachedMemory:    info.Cached,
+		AvailableMemory: info.Available,
+		SwapTotal:       info.SwapTotal,
+		SwapUsed:        info.SwapUsed,
+		TotalCpu:        info.TotalCPU,
+		FreeCpu:         info.FreeCPU,
+		UsedCpu:         info.UsedCPU,
+		Cpus:            telemetryCPUs,
+		Disks:           telemetryDisks,
+		DiskIo:          telemetryDiskIO,
+		Networks:        telemetryNetworks,
+		System:          telemetrySystem,
+		Processes:       telemetryProcesses,
+		Sensors:         telemetrySensors,
+		Cgroups:         telemetryCgroups,
+		CollectorErrors: telemetryErrors,
+		Units:           telemetryUnits,
+		Certificates:    telemetryCertificates,
+	}
+}
+
+// toMetricsRequest maps the custom metrics of a tick onto what is sent to
+// ingest, metrics without a timestamp get the collection time.
+func toMetricsRequest(identifier string, info systeminformation.SystemInformation) *v1.SendMetricsRequest {
+	customMetrics := iter.Map(info.Metrics, func(metric metrics.Metric) *v1.CustomMetric {
+		timestamp := metric.Timestamp
+		if timestamp.IsZero() {
+			timestamp = info.Timestamp
+		}
+
+		return &v1.CustomMetric{
+			Timestamp: timestamppb.New(timestamp),
+			Source:    metric.Source,
+			Name:      metric.Name,
+			Type:      metric.Type,
+			Value:     metric.Value,
+			Labels:    metric.Labels,
+		}
+	})
+
+	return &v1.SendMetricsRequest{
+		Identifier: identifier,
+		Metrics:    customMetrics,
+	}
+}
+
+// maxLogsRequestBytes keeps every logs request well under the 4 MiB grpc
+// servers accept by default.
+const maxLogsRequestBytes = 1 << 20
+
+// toLogsRequests maps the log lines of a tick onto what is sent to ingest,
+// split into requests of at most maxLogsRequestBytes.
+func toLogsRequests(identifier string, info systeminformation.SystemInformation) []*v1.SendLogsRequest {
+	var requests []*v1.SendLogsRequest
+
+	current := &v1.SendLogsRequest{Identifier: identifier}
+	size := proto.Size(current)
+	for _, entry := range info.Logs {
+		logEntry := &v1.LogEntry{
+			Timestamp: timestamppb.New(entry.Timestamp),
+			Source:    entry.Source,
+			Path:      entry.Path,
+			Severity:  entry.Severity,
+			Message:   entry.Message,
+			Fields:    entry.Fields,
+		}
+
+		// the tag and length prefix of the repeated field take a few bytes
+		entrySize := proto.Size(logEntry) + 8
+		if len(current.Entries) > 0 && size+entrySize > maxLogsRequestBytes {
+			requests = append(requests, current)
+			current = &v1.SendLogsRequest{Identifier: identifier}
+			size = proto.Size(current)
+		}
+
+		current.Entries = append(current.Entries, logEntry)
+		size += entrySize
+	}
+
+	if len(current.Entries) > 0 {
+		requests = append(requests, current)
+	}
+
+	return requests
+}
+
+// toProbeResultsRequest maps the probes run on a tick onto what is sent to
+// ingest.
+func toProbeResultsRequest(identifier string, info systeminformation.SystemInformation) *v1.SendProbeResultsRequest {
+	results := iter.Map(info.Probes, func(probe systeminformation.SystemInformationProbe) *v1.ProbeResult {
+		result := &v1.ProbeResult{
+			Timestamp:  timestamppb.New(probe.Timestamp),
+			Name:       probe.Name,
+			Kind:       probe.Kind,
+			Target:     probe.Target,
+			Success:    probe.Success,
+			LatencyMs:  float64(probe.Latency) / float64(time.Millisecond),
+			StatusCode: int32(probe.StatusCode),
+			Error:      probe.Error,
+		}
+		if !probe.CertNotAfter.IsZero() {
+			result.CertNotAfter = timestamppb.New(probe.CertNotAfter)
+		}
+
+		return result
+	})
+
+	return &v1.SendProbeResultsRequest{
+		Identifier: identifier,
+		Results:    results,
+	}
+}
+
+// toInventory maps the host inventory onto what is sent along health checks.
+func toInventory(inventory systeminformation.Inventory) *v1.DeviceInventory {
+	interfaces := iter.Map(inventory.Interfaces, func(iface systeminformation.InventoryInterface) *v1.InventoryInterface {
+		return &v1.InventoryInterface{
+			Name:      iface.Name,
+			Mac:       iface.MAC,
+			Addresses: iface.Addresses,
+		}
+	})
+
+	return &v1.DeviceInventory{
+		Hostname:             inventory.Hostname,
+		Os:                   inventory.OS,
+		Platform:             inventory.Platform,
+		PlatformFamily:       inventory.PlatformFamily,
+		PlatformVersion:      inventory.PlatformVersion,
+		KernelVersion:        inventory.KernelVersion,
+		Arch:                 inventory.Arch,
+		CpuModel:             inventory.CPUModel,
+		CpuCores:             inventory.CPUCores,
+		CpuThreads:           inventory.CPUThreads,
+		TotalMemory:          inventory.TotalMemory,
+		BootTime:             timestamppb.New(inventory.BootTime),
+		Interfaces:           interfaces,
+		AgentVersion:         inventory.AgentVersion,
+		VirtualizationSystem: inventory.VirtualizationSystem,
+		VirtualizationRole:   inventory.VirtualizationRole,
+	}
+}
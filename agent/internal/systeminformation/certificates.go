@@ -0,0 +1,219 @@
+package systeminformation
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxCertificateFileBytes skips files too big to be certificates, e.g. a
+// directory that also holds logs
+const maxCertificateFileBytes = 1 << 20
+
+const (
+	CertificateSourceFile     = "file"
+	CertificateSourceEndpoint = "endpoint"
+)
+
+type SystemInformationCertificate struct {
+	// file or endpoint
+	Source string
+	// the file path or the endpoint host:port
+	Location string
+	// sha256 of the DER encoding, hex encoded
+	Fingerprint string
+	Subject     string
+	Issuer      string
+	SANs        []string
+	NotBefore   time.Time
+	NotAfter    time.Time
+	// whole days left when scanned, negative once expired
+	DaysRemaining int32
+}
+
+type CertificateCollector struct {
+	Schedule
+	paths     []string
+	endpoints []string
+	dialer    *net.Dialer
+}
+
+// NewCertificateCollector reports every certificate in the PEM files
+// matching paths, directories are walked, and every certificate the
+// endpoints present in their TLS handshake.
+func NewCertificateCollector(schedule Schedule, paths []string, endpoints []string) *CertificateCollector {
+	return &CertificateCollector{
+		Schedule:  schedule,
+		paths:     paths,
+		endpoints: endpoints,
+		dialer:    &net.Dialer{},
+	}
+}
+
+func (c *CertificateCollector) Name() string {
+	return "certificates"
+}
+
+// Collect reports what it could read, failing files and endpoints are
+// joined in the error.
+func (c *CertificateCollector) Collect(ctx context.Context) (Result, error) {
+	var certificates []SystemInformationCertificate
+	var errs []error
+
+	for _, file := range c.files() {
+		found, err := readCertificateFile(file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		certificates = append(certificates, found...)
+	}
+
+	for _, endpoint := range c.endpoints {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+
+		found, err := c.endpointCertificates(ctx, endpoint)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		certificates = append(certificates, found...)
+	}
+
+	return func(info *SystemInformation) {
+		info.Certificates = append(info.Certificates, certificates...)
+	}, errors.Join(errs...)
+}
+
+// files expands the patterns, walking the directories they match.
+func (c *CertificateCollector) files() []string {
+	var files []string
+	for _, pattern := range c.paths {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+
+		for _, match := range matches {
+			// unreadable entries show up as errors when reading them
+			_ = filepath.WalkDir(match, func(path string, entry fs.DirEntry, err error) error {
+				if err == nil && !entry.IsDir() {
+					files = append(files, path)
+				}
+				return nil
+			})
+		}
+	}
+
+	return files
+}
+
+// readCertificateFile returns the certificates among the PEM blocks of
+// path, files without any are skipped as directories also hold keys.
+func readCertificateFile(path string) ([]SystemInformationCertificate, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxCertificateFileBytes {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var certificates []SystemInformationCertificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return certificates, errors.Join(fmt.Errorf("failed to parse certificate in %s", path), err)
+		}
+
+		certificates = append(certificates, newCertificate(CertificateSourceFile, path, cert))
+	}
+
+	return certificates, nil
+}
+
+// endpointCertificates returns the chain endpoint presents, without
+// verifying it: expired and self signed certificates are what this is
+// looking for.
+func (c *CertificateCollector) endpointCertificates(ctx context.Context, endpoint string) ([]SystemInformationCertificate, error) {
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: c.dialer,
+		Config: &tls.Config{
+			ServerName:         host,
+			InsecureSkipVerify: true,
+		},
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to connect to %s", endpoint), err)
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+
+	certificates := make([]SystemInformationCertificate, 0, len(state.PeerCertificates))
+	for _, cert := range state.PeerCertificates {
+		certificates = append(certificates, newCertificate(CertificateSourceEndpoint, endpoint, cert))
+	}
+
+	return certificates, nil
+}
+
+func newCertificate(source string, location string, cert *x509.Certificate) SystemInformationCertificate {
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	return SystemInformationCertificate{
+		Source:        source,
+		Location:      location,
+		Fingerprint:   hex.EncodeToString(fingerprint[:]),
+		Subject:       cert.Subject.String(),
+		Issuer:        cert.Issuer.String(),
+		SANs:          sans,
+		NotBefore:     cert.NotBefore,
+		NotAfter:      cert.NotAfter,
+		DaysRemaining: int32(math.Floor(time.Until(cert.NotAfter).Hours() / 24)),
+	}
+}
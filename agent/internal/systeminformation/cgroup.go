@@ -0,0 +1,336 @@
+package systeminformation
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNoCgroupV2 = errors.New("cgroup v2 is not mounted")
+
+type SystemInformationCgroup struct {
+	// relative to the cgroup root, e.g. "/system.slice/docker-<id>.scope"
+	Path               string
+	ContainerID        string
+	CPUUsageUsec       uint64
+	CPUPercent         float64
+	ThrottledPeriods   uint64
+	MemoryCurrent      uint64
+	MemoryMax          uint64
+	PidsCurrent        uint64
+	PidsMax            uint64
+	IOReadBytes        uint64
+	IOWriteBytes       uint64
+	IOReadBytesPerSec  float64
+	IOWriteBytesPerSec float64
+}
+
+// containerIDPattern matches the scopes docker, containerd, cri-o and podman
+// create, as well as the bare ids of cgroupfs drivers.
+var containerIDPattern = regexp.MustCompile(`(?:^|[-/])([0-9a-f]{64})(?:\.scope)?$`)
+
+func cgroupRoot() string {
+	return sysPath("fs", "cgroup")
+}
+
+// readCgroupValue reads a single value file, "max" is reported as zero.
+func readCgroupValue(path string) (uint64, error) {
+	val, err := readTrimmed(path)
+	if err != nil {
+		return 0, err
+	}
+
+	if val == "max" {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(val, 10, 64)
+}
+
+// readCgroupKeyed reads the "key value" files like cpu.stat.
+func readCgroupKeyed(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := map[string]uint64{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, val, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+
+		if parsed, err := strconv.ParseUint(val, 10, 64); err == nil {
+			values[key] = parsed
+		}
+	}
+
+	return values, scanner.Err()
+}
+
+// readCgroupIO sums io.stat over every device, its lines look like
+// "8:0 rbytes=1 wbytes=2 rios=3 wios=4 dbytes=0 dios=0".
+func readCgroupIO(path string) (uint64, uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	var read, write uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			key, val, _ := strings.Cut(field, "=")
+			parsed, err := strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				continue
+			}
+
+			switch key {
+			case "rbytes":
+				read += parsed
+			case "wbytes":
+				write += parsed
+			}
+		}
+	}
+
+	return read, write, scanner.Err()
+}
+
+// readCgroup reads the stats of a single cgroup directory, controllers that
+// aren't enabled for it are left at zero.
+func readCgroup(dir string) SystemInformationCgroup {
+	var cgroup SystemInformationCgroup
+
+	if stat, err := readCgroupKeyed(filepath.Join(dir, "cpu.stat")); err == nil {
+		cgroup.CPUUsageUsec = stat["usage_usec"]
+		cgroup.ThrottledPeriods = stat["nr_throttled"]
+	}
+
+	cgroup.MemoryCurrent, _ = readCgroupValue(filepath.Join(dir, "memory.current"))
+	cgroup.MemoryMax, _ = readCgroupValue(filepath.Join(dir, "memory.max"))
+	cgroup.PidsCurrent, _ = readCgroupValue(filepath.Join(dir, "pids.current"))
+	cgroup.PidsMax, _ = readCgroupValue(filepath.Join(dir, "pids.max"))
+	cgroup.IOReadBytes, cgroup.IOWriteBytes, _ = readCgroupIO(filepath.Join(dir, "io.stat"))
+
+	return cgroup
+}
+
+type cgroupSample struct {
+	at     time.Time
+	cgroup SystemInformationCgroup
+}
+
+// cgroupDeltas keeps the previous stats per cgroup path to turn them into
+// rates.
+type cgroupDeltas struct {
+	previous map[string]cgroupSample
+}
+
+// collect walks the cgroup hierarchy and reports the top level cgroups,
+// e.g. system.slice or kubepods.slice, and every container below them.
+func (d *cgroupDeltas) collect(ctx context.Context) ([]SystemInformationCgroup, error) {
+	root := cgroupRoot()
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err != nil {
+		return nil, ErrNoCgroupV2
+	}
+
+	now := time.Now()
+	current := map[string]cgroupSample{}
+	var cgroups []SystemInformationCgroup
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// cgroups come and go while walking
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !entry.IsDir() || path == root {
+			return nil
+		}
+
+		rel := strings.TrimPrefix(path, root)
+		match := containerIDPattern.FindStringSubmatch(rel)
+		if match == nil && strings.Count(rel, "/") > 1 {
+			return nil
+		}
+
+		cgroup := readCgroup(path)
+		cgroup.Path = rel
+		if match != nil {
+			cgroup.ContainerID = match[1]
+		}
+
+		if prev, ok := d.previous[rel]; ok {
+			elapsed := now.Sub(prev.at).Seconds()
+
+			rate := func(prev uint64, curr uint64) float64 {
+				if curr < prev || elapsed <= 0 {
+					return 0
+				}
+				return float64(curr-prev) / elapsed
+			}
+
+			cgroup.CPUPercent = rate(prev.cgroup.CPUUsageUsec, cgroup.CPUUsageUsec) / 1e6 * 100
+			cgroup.IOReadBytesPerSec = rate(prev.cgroup.IOReadBytes, cgroup.IOReadBytes)
+			cgroup.IOWriteBytesPerSec = rate(prev.cgroup.IOWriteBytes, cgroup.IOWriteBytes)
+		}
+
+		current[rel] = cgroupSample{at: now, cgroup: cgroup}
+		cgroups = append(cgroups, cgroup)
+
+		// nothing below a container is reported
+		if match != nil {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+
+	d.previous = current
+
+	return cgroups, err
+}
+
+// ownCgroup resolves the cgroup of the agent from /proc/self/cgroup, with a
+// cgroup namespace it's the root of the mounted hierarchy.
+func ownCgroup() (string, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return filepath.Join(cgroupRoot(), path), nil
+		}
+	}
+
+	return "", ErrNoCgroupV2
+}
+
+// cpuLimit returns the number of cpus the cgroup may use, the host's when
+// it isn't limited.
+func cpuLimit(dir string) float64 {
+	val, err := readTrimmed(filepath.Join(dir, "cpu.max"))
+	if err != nil {
+		return float64(runtime.NumCPU())
+	}
+
+	quota, period, ok := strings.Cut(val, " ")
+	if !ok || quota == "max" {
+		return float64(runtime.NumCPU())
+	}
+
+	quotaUsec, err := strconv.ParseFloat(quota, 64)
+	if err != nil {
+		return float64(runtime.NumCPU())
+	}
+	periodUsec, err := strconv.ParseFloat(period, 64)
+	if err != nil || periodUsec <= 0 {
+		return float64(runtime.NumCPU())
+	}
+
+	return quotaUsec / periodUsec
+}
+
+// ownCgroupDir returns the agent's own cgroup directory, the in container
+// mode reports its limits instead of the host's totals.
+func ownCgroupDir() (string, error) {
+	dir, err := ownCgroup()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cgroup.controllers")); err != nil {
+		return "", ErrNoCgroupV2
+	}
+
+	return dir, nil
+}
+
+// applyContainerMemory caps the memory totals of info to the limit of the
+// agent's cgroup.
+func applyContainerMemory(info *SystemInformation) error {
+	dir, err := ownCgroupDir()
+	if err != nil {
+		return err
+	}
+
+	current, err := readCgroupValue(filepath.Join(dir, "memory.current"))
+	if err != nil {
+		return err
+	}
+
+	// without a limit the host total stays the ceiling
+	if limit, _ := readCgroupValue(filepath.Join(dir, "memory.max")); limit > 0 && limit < info.TotalMemory {
+		info.TotalMemory = limit
+	}
+
+	info.UsedMemory = min(current, info.TotalMemory)
+	info.FreeMemory = info.TotalMemory - info.UsedMemory
+	info.Available = info.FreeMemory
+
+	return nil
+}
+
+// containerCPU keeps the previous cpu times of the agent's cgroup.
+type containerCPU struct {
+	at     time.Time
+	user   uint64
+	system uint64
+}
+
+// utilisation returns the cgroup's usage since the previous call relative
+// to its cpu limit, nothing is reported on the first call.
+func (c *containerCPU) utilisation() ([]SystemInformationCPU, error) {
+	dir, err := ownCgroupDir()
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := readCgroupKeyed(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user, system := stat["user_usec"], stat["system_usec"]
+
+	var cpus []SystemInformationCPU
+	if !c.at.IsZero() && user >= c.user && system >= c.system {
+		capacity := now.Sub(c.at).Seconds() * 1e6 * cpuLimit(dir)
+		if capacity > 0 {
+			userPercent := float32(float64(user-c.user) / capacity * 100)
+			systemPercent := float32(float64(system-c.system) / capacity * 100)
+
+			cpus = []SystemInformationCPU{{
+				Name:   "cpu-total",
+				User:   userPercent,
+				System: systemPercent,
+				Idle:   max(100-userPercent-systemPercent, 0),
+			}}
+		}
+	}
+
+	c.at, c.user, c.system = now, user, system
+
+	return cpus, nil
+}
@@ -0,0 +1,169 @@
+package systeminformation
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrCollectorTimeout = errors.New("collector timed out")
+
+var ErrCollectorBusy = errors.New("collector is still running a previous collection")
+
+// Result is what a collector gathered, it's applied onto the information
+// sent to ingest on the tick that ran the collector.
+type Result func(info *SystemInformation)
+
+// Collector gathers one part of the system information. Collect is never
+// called concurrently for the same collector, it may keep state between
+// calls to report rates.
+type Collector interface {
+	Name() string
+	// Interval between two runs, zero runs it on every metric tick
+	Interval() time.Duration
+	// Timeout after which the registry stops waiting for Collect
+	Timeout() time.Duration
+	// Collect may return a partial result along with an error
+	Collect(ctx context.Context) (Result, error)
+}
+
+// Schedule is embedded by collectors to implement Interval and Timeout.
+type Schedule struct {
+	interval time.Duration
+	timeout  time.Duration
+}
+
+func NewSchedule(interval time.Duration, timeout time.Duration) Schedule {
+	return Schedule{interval: interval, timeout: timeout}
+}
+
+func (s Schedule) Interval() time.Duration {
+	return s.interval
+}
+
+func (s Schedule) Timeout() time.Duration {
+	return s.timeout
+}
+
+type CollectorError struct {
+	Collector string
+	Err       error
+}
+
+type entry struct {
+	collector Collector
+	lastRun   time.Time
+	// set while Collect runs, including after the registry gave up on it
+	busy atomic.Bool
+}
+
+// due reports whether the collector's interval elapsed, with some slack so
+// ticker jitter doesn't push a run to the next tick.
+func (e *entry) due(now time.Time) bool {
+	interval := e.collector.Interval()
+	return e.lastRun.IsZero() || now.Sub(e.lastRun) >= interval-interval/10
+}
+
+// Registry runs collectors concurrently, each under its own timeout so a
+// hung collector, e.g. disk usage on a stale NFS mount, doesn't hold back
+// the others.
+type Registry struct {
+	entries []*entry
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) Register(collector Collector) {
+	r.entries = append(r.entries, &entry{collector: collector})
+}
+
+// Close releases what the collectors hold on to, e.g. the files they tail,
+// once the registry is replaced.
+func (r *Registry) Close() error {
+	var errs []error
+	for _, e := range r.entries {
+		if closer, ok := e.collector.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Collect runs every collector that is due and merges their results. A
+// collector that timed out is skipped until its pending call returns, its
+// late result is dropped.
+func (r *Registry) Collect(ctx context.Context) SystemInformation {
+	now := time.Now()
+	info := SystemInformation{
+		Timestamp: now,
+	}
+
+	type outcome struct {
+		result Result
+		err    CollectorError
+	}
+
+	outcomes := make([]outcome, len(r.entries))
+	var wg sync.WaitGroup
+	for idx, e := range r.entries {
+		if !e.due(now) {
+			continue
+		}
+
+		name := e.collector.Name()
+		if !e.busy.CompareAndSwap(false, true) {
+			outcomes[idx].err = CollectorError{Collector: name, Err: ErrCollectorBusy}
+			continue
+		}
+		e.lastRun = now
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			collectCtx, cancel := context.WithTimeout(ctx, e.collector.Timeout())
+			defer cancel()
+
+			type collected struct {
+				result Result
+				err    error
+			}
+
+			done := make(chan collected, 1)
+			go func() {
+				defer e.busy.Store(false)
+
+				result, err := e.collector.Collect(collectCtx)
+				done <- collected{result: result, err: err}
+			}()
+
+			select {
+			case c := <-done:
+				outcomes[idx].result = c.result
+				if c.err != nil {
+					outcomes[idx].err = CollectorError{Collector: name, Err: c.err}
+				}
+			case <-collectCtx.Done():
+				outcomes[idx].err = CollectorError{Collector: name, Err: ErrCollectorTimeout}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, o := range outcomes {
+		if o.result != nil {
+			o.result(&info)
+		}
+		if o.err.Err != nil {
+			info.Errors = append(info.Errors, o.err)
+		}
+	}
+
+	return info
+}
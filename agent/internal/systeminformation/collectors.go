@@ -0,0 +1,290 @@
+package systeminformation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/mem"
+)
+
+type MemoryCollector struct {
+	Schedule
+	inContainer bool
+}
+
+// NewMemoryCollector reports the host memory, or the limits of the agent's
+// own cgroup when inContainer is set.
+func NewMemoryCollector(schedule Schedule, inContainer bool) *MemoryCollector {
+	return &MemoryCollector{Schedule: schedule, inContainer: inContainer}
+}
+
+func (c *MemoryCollector) Name() string {
+	return "memory"
+}
+
+func (c *MemoryCollector) Collect(ctx context.Context) (Result, error) {
+	v, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var partial SystemInformation
+	partial.TotalMemory = v.Total
+	partial.FreeMemory = v.Free
+	partial.UsedMemory = v.Used
+	partial.Buffers = v.Buffers
+	partial.Cached = v.Cached
+	partial.Available = v.Available
+
+	var errs []error
+	// hosts without swap still report zeroes, an error means it can't be read
+	if swap, err := mem.SwapMemoryWithContext(ctx); err == nil {
+		partial.SwapTotal = swap.Total
+		partial.SwapUsed = swap.Used
+	} else {
+		errs = append(errs, errors.Join(errors.New("failed to read swap"), err))
+	}
+
+	if c.inContainer {
+		if err := applyContainerMemory(&partial); err != nil {
+			errs = append(errs, errors.Join(errors.New("failed to read container memory"), err))
+		}
+	}
+
+	return func(info *SystemInformation) {
+		info.TotalMemory = partial.TotalMemory
+		info.FreeMemory = partial.FreeMemory
+		info.UsedMemory = partial.UsedMemory
+		info.Buffers = partial.Buffers
+		info.Cached = partial.Cached
+		info.Available = partial.Available
+		info.SwapTotal = partial.SwapTotal
+		info.SwapUsed = partial.SwapUsed
+	}, errors.Join(errs...)
+}
+
+type CPUCollector struct {
+	Schedule
+	perCore     bool
+	inContainer bool
+	deltas      cpuDeltas
+	container   containerCPU
+}
+
+// NewCPUCollector reports the utilisation of the whole host and of every
+// core with perCore, or of the agent's own cgroup with inContainer.
+func NewCPUCollector(schedule Schedule, perCore bool, inContainer bool) *CPUCollector {
+	return &CPUCollector{Schedule: schedule, perCore: perCore, inContainer: inContainer}
+}
+
+func (c *CPUCollector) Name() string {
+	return "cpu"
+}
+
+func (c *CPUCollector) Collect(ctx context.Context) (Result, error) {
+	stats, err := cpu.TimesWithContext(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(stats) == 0 {
+		return nil, errors.New("no cpu times reported")
+	}
+
+	currStats := stats[0]
+	totalCPU := currStats.User + currStats.System + currStats.Idle + currStats.Nice +
+		currStats.Iowait + currStats.Irq + currStats.Softirq + currStats.Steal +
+		currStats.Guest + currStats.GuestNice
+	freeCPU := currStats.Idle + currStats.Iowait
+	usedCPU := totalCPU - freeCPU
+
+	var cpus []SystemInformationCPU
+	if c.inContainer {
+		cpus, err = c.container.utilisation()
+	} else {
+		cpus, err = c.deltas.utilisation(ctx, c.perCore)
+	}
+
+	return func(info *SystemInformation) {
+		info.TotalCPU = float32(totalCPU)
+		info.FreeCPU = float32(freeCPU)
+		info.UsedCPU = float32(usedCPU)
+		info.CPUs = cpus
+	}, err
+}
+
+type DiskCollector struct {
+	Schedule
+	filter DiskFilter
+	io     diskIODeltas
+}
+
+// NewDiskCollector reports the usage of the partitions matching filter and
+// the activity of every block device.
+func NewDiskCollector(schedule Schedule, filter DiskFilter) *DiskCollector {
+	return &DiskCollector{Schedule: schedule, filter: filter}
+}
+
+func (c *DiskCollector) Name() string {
+	return "disk"
+}
+
+func (c *DiskCollector) Collect(ctx context.Context) (Result, error) {
+	parts, err := disk.PartitionsWithContext(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	disks := make([]SystemInformationDisk, 0, len(parts))
+	for _, part := range parts {
+		if !c.filter.Match(part) {
+			continue
+		}
+
+		usage, err := disk.UsageWithContext(ctx, part.Mountpoint)
+		if err != nil {
+			// unreadable mounts are left out rather than reported empty
+			errs = append(errs, fmt.Errorf("failed to read usage of %s: %w", part.Mountpoint, err))
+			continue
+		}
+
+		disks = append(disks, SystemInformationDisk{
+			Label:       part.Device,
+			Mountpoint:  part.Mountpoint,
+			FSType:      part.Fstype,
+			Total:       usage.Total,
+			Free:        usage.Free,
+			Used:        usage.Used,
+			InodesTotal: usage.InodesTotal,
+			InodesUsed:  usage.InodesUsed,
+			InodesFree:  usage.InodesFree,
+		})
+	}
+
+	diskIO, err := c.io.collect(ctx)
+	if err != nil {
+		errs = append(errs, errors.Join(errors.New("failed to read disk io counters"), err))
+	}
+
+	return func(info *SystemInformation) {
+		info.Disks = disks
+		info.DiskIO = diskIO
+	}, errors.Join(errs...)
+}
+
+type NetworkCollector struct {
+	Schedule
+	deltas networkDeltas
+}
+
+func NewNetworkCollector(schedule Schedule) *NetworkCollector {
+	return &NetworkCollector{Schedule: schedule}
+}
+
+func (c *NetworkCollector) Name() string {
+	return "network"
+}
+
+func (c *NetworkCollector) Collect(ctx context.Context) (Result, error) {
+	networks, err := c.deltas.collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(info *SystemInformation) {
+		info.Networks = networks
+	}, nil
+}
+
+type SystemCollector struct {
+	Schedule
+}
+
+func NewSystemCollector(schedule Schedule) *SystemCollector {
+	return &SystemCollector{Schedule: schedule}
+}
+
+func (c *SystemCollector) Name() string {
+	return "system"
+}
+
+func (c *SystemCollector) Collect(ctx context.Context) (Result, error) {
+	system, err := collectSystem(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(info *SystemInformation) {
+		info.System = system
+	}, nil
+}
+
+type ProcessCollector struct {
+	Schedule
+	top    int
+	deltas processDeltas
+}
+
+// NewProcessCollector reports the top processes by cpu and by memory.
+func NewProcessCollector(schedule Schedule, top int) *ProcessCollector {
+	return &ProcessCollector{Schedule: schedule, top: top}
+}
+
+func (c *ProcessCollector) Name() string {
+	return "process"
+}
+
+func (c *ProcessCollector) Collect(ctx context.Context) (Result, error) {
+	processes, err := c.deltas.top(ctx, c.top)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(info *SystemInformation) {
+		info.Processes = processes
+	}, nil
+}
+
+type SensorsCollector struct {
+	Schedule
+}
+
+func NewSensorsCollector(schedule Schedule) *SensorsCollector {
+	return &SensorsCollector{Schedule: schedule}
+}
+
+func (c *SensorsCollector) Name() string {
+	return "sensors"
+}
+
+func (c *SensorsCollector) Collect(ctx context.Context) (Result, error) {
+	sensors, err := collectSensors(ctx)
+
+	return func(info *SystemInformation) {
+		info.Sensors = sensors
+	}, err
+}
+
+type CgroupCollector struct {
+	Schedule
+	deltas cgroupDeltas
+}
+
+func NewCgroupCollector(schedule Schedule) *CgroupCollector {
+	return &CgroupCollector{Schedule: schedule}
+}
+
+func (c *CgroupCollector) Name() string {
+	return "cgroup"
+}
+
+func (c *CgroupCollector) Collect(ctx context.Context) (Result, error) {
+	cgroups, err := c.deltas.collect(ctx)
+
+	return func(info *SystemInformation) {
+		info.Cgroups = cgroups
+	}, err
+}
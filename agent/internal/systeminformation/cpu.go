@@ -0,0 +1,72 @@
+package systeminformation
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/cpu"
+)
+
+type SystemInformationCPU struct {
+	Name   string
+	User   float32
+	System float32
+	Iowait float32
+	Steal  float32
+	Idle   float32
+}
+
+// cpuDeltas keeps the previous cumulative times per cpu, utilisation only
+// makes sense between two samples.
+type cpuDeltas struct {
+	previous map[string]cpu.TimesStat
+}
+
+func percentOf(delta float64, total float64) float32 {
+	return float32(max(delta, 0) / total * 100)
+}
+
+// utilisation returns the usage since the previous call, nothing is reported
+// for a cpu on its first sample or after its counters went backwards.
+func (d *cpuDeltas) utilisation(ctx context.Context, perCore bool) ([]SystemInformationCPU, error) {
+	stats, err := cpu.TimesWithContext(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if perCore {
+		coreStats, err := cpu.TimesWithContext(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, coreStats...)
+	}
+
+	current := make(map[string]cpu.TimesStat, len(stats))
+	cpus := make([]SystemInformationCPU, 0, len(stats))
+	for _, curr := range stats {
+		current[curr.CPU] = curr
+
+		prev, ok := d.previous[curr.CPU]
+		if !ok {
+			continue
+		}
+
+		total := curr.Total() - prev.Total()
+		if total <= 0 {
+			continue
+		}
+
+		cpus = append(cpus, SystemInformationCPU{
+			Name:   curr.CPU,
+			User:   percentOf((curr.User+curr.Nice)-(prev.User+prev.Nice), total),
+			System: percentOf((curr.System+curr.Irq+curr.Softirq)-(prev.System+prev.Irq+prev.Softirq), total),
+			Iowait: percentOf(curr.Iowait-prev.Iowait, total),
+			Steal:  percentOf(curr.Steal-prev.Steal, total),
+			Idle:   percentOf(curr.Idle-prev.Idle, total),
+		})
+	}
+
+	d.previous = current
+
+	return cpus, nil
+}
@@ -0,0 +1,95 @@
+package systeminformation
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/shirou/gopsutil/disk"
+)
+
+type SystemInformationDiskIO struct {
+	Name             string
+	ReadBytes        uint64
+	WriteBytes       uint64
+	ReadCount        uint64
+	WriteCount       uint64
+	IoTime           uint64
+	WeightedIoTime   uint64
+	IopsInProgress   uint64
+	ReadBytesPerSec  float64
+	WriteBytesPerSec float64
+	ReadsPerSec      float64
+	WritesPerSec     float64
+	Utilisation      float64
+	QueueSize        float64
+}
+
+type diskIOSample struct {
+	at       time.Time
+	counters disk.IOCountersStat
+}
+
+// diskIODeltas keeps the previous counters per block device to turn them
+// into rates.
+type diskIODeltas struct {
+	previous map[string]diskIOSample
+}
+
+func (d *diskIODeltas) collect(ctx context.Context) ([]SystemInformationDiskIO, error) {
+	ioCounters, err := disk.IOCountersWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(ioCounters))
+	for name := range ioCounters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	current := make(map[string]diskIOSample, len(ioCounters))
+	diskStats := make([]SystemInformationDiskIO, len(names))
+	for idx, name := range names {
+		dev := ioCounters[name]
+		current[name] = diskIOSample{at: now, counters: dev}
+
+		stat := SystemInformationDiskIO{
+			Name:           name,
+			ReadBytes:      dev.ReadBytes,
+			WriteBytes:     dev.WriteBytes,
+			ReadCount:      dev.ReadCount,
+			WriteCount:     dev.WriteCount,
+			IoTime:         dev.IoTime,
+			WeightedIoTime: dev.WeightedIO,
+			IopsInProgress: dev.IopsInProgress,
+		}
+
+		if prev, ok := d.previous[name]; ok {
+			elapsed := now.Sub(prev.at).Seconds()
+
+			rate := func(prev uint64, curr uint64) float64 {
+				delta, ok := counterDelta(prev, curr)
+				if !ok || elapsed <= 0 {
+					return 0
+				}
+				return float64(delta) / elapsed
+			}
+
+			stat.ReadBytesPerSec = rate(prev.counters.ReadBytes, dev.ReadBytes)
+			stat.WriteBytesPerSec = rate(prev.counters.WriteBytes, dev.WriteBytes)
+			stat.ReadsPerSec = rate(prev.counters.ReadCount, dev.ReadCount)
+			stat.WritesPerSec = rate(prev.counters.WriteCount, dev.WriteCount)
+			// both times are in milliseconds per second of wall time
+			stat.Utilisation = min(rate(prev.counters.IoTime, dev.IoTime)/1000*100, 100)
+			stat.QueueSize = rate(prev.counters.WeightedIO, dev.WeightedIO) / 1000
+		}
+
+		diskStats[idx] = stat
+	}
+
+	d.previous = current
+
+	return diskStats, nil
+}
@@ -0,0 +1,72 @@
+package systeminformation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/microwatcher/agent/internal/metrics"
+)
+
+// maxStderr bounds how much of a failing command's stderr ends up in the
+// collector error.
+const maxStderr = 512
+
+type ExecCollector struct {
+	Schedule
+	name    string
+	command []string
+	format  string
+}
+
+// NewExecCollector runs command and parses its stdout as format, see
+// metrics.Formats. The command is killed once the timeout expires.
+func NewExecCollector(schedule Schedule, name string, command []string, format string) *ExecCollector {
+	return &ExecCollector{Schedule: schedule, name: name, command: command, format: format}
+}
+
+func (c *ExecCollector) Name() string {
+	return "exec:" + c.name
+}
+
+func (c *ExecCollector) Collect(ctx context.Context) (Result, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.command[0], c.command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// don't wait forever on children that inherited the pipes
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > maxStderr {
+			msg = msg[:maxStderr]
+		}
+
+		return nil, errors.Join(fmt.Errorf("command failed: %s", msg), err)
+	}
+
+	var parsed []metrics.Metric
+	var err error
+	switch c.format {
+	case metrics.FormatJSON:
+		parsed, err = metrics.ParseJSONLines(&stdout)
+	default:
+		parsed, err = metrics.ParsePrometheus(&stdout)
+	}
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to parse command output"), err)
+	}
+
+	for idx := range parsed {
+		parsed[idx].Source = c.name
+	}
+
+	return func(info *SystemInformation) {
+		info.Metrics = append(info.Metrics, parsed...)
+	}, nil
+}
@@ -1,91 +1,70 @@
 package systeminformation
 
 import (
+	"errors"
 	"time"
 
-	"github.com/shirou/gopsutil/cpu"
-	"github.com/shirou/gopsutil/disk"
-	"github.com/shirou/gopsutil/mem"
-	"github.com/shirou/gopsutil/net"
+	"github.com/microwatcher/agent/internal/metrics"
 )
 
-type SystemInformationNetwork struct {
-	Name      string
-	BytesSent uint64
-	BytesRecv uint64
-}
-
 type SystemInformationDisk struct {
-	Label      string
-	Mountpoint string
-	Total      uint64
-	Free       uint64
-	Used       uint64
+	Label       string
+	Mountpoint  string
+	FSType      string
+	Total       uint64
+	Free        uint64
+	Used        uint64
+	InodesTotal uint64
+	InodesUsed  uint64
+	InodesFree  uint64
 }
 
 type SystemInformation struct {
-	Timestamp   time.Time
-	TotalMemory uint64
-	FreeMemory  uint64
-	UsedMemory  uint64
-	TotalCPU    float32
-	FreeCPU     float32
-	UsedCPU     float32
-	Disks       []SystemInformationDisk
-	Networks    []SystemInformationNetwork
-}
-
-func GetSystemInformation() SystemInformation {
-	v, _ := mem.VirtualMemory()
-	// TODO: assert it doesn't fail
-
-	stats, _ := cpu.Times(false)
-	// TODO: assert it doesn't fail
-
-	currStats := stats[0]
-	totalCPU := currStats.User + currStats.System + currStats.Idle + currStats.Nice +
-		currStats.Iowait + currStats.Irq + currStats.Softirq + currStats.Steal +
-		currStats.Guest + currStats.GuestNice
-	freeCPU := currStats.Idle + currStats.Iowait
-	usedCPU := totalCPU - freeCPU
+	Timestamp    time.Time
+	TotalMemory  uint64
+	FreeMemory   uint64
+	UsedMemory   uint64
+	Buffers      uint64
+	Cached       uint64
+	Available    uint64
+	SwapTotal    uint64
+	SwapUsed     uint64
+	TotalCPU     float32
+	FreeCPU      float32
+	UsedCPU      float32
+	CPUs         []SystemInformationCPU
+	Disks        []SystemInformationDisk
+	DiskIO       []SystemInformationDiskIO
+	Networks     []SystemInformationNetwork
+	System       *SystemInformationSystem
+	Processes    []SystemInformationProcess
+	Sensors      []SystemInformationSensor
+	Cgroups      []SystemInformationCgroup
+	Units        []SystemInformationUnit
+	Certificates []SystemInformationCertificate
+	Metrics      []metrics.Metric
+	Logs         []SystemInformationLog
+	Probes       []SystemInformationProbe
+	Errors       []CollectorError
 
-	parts, _ := disk.Partitions(true)
-	// TODO: assert it doesn't fail
-
-	systemDisks := make([]SystemInformationDisk, len(parts))
-	for i, part := range parts {
-		usage, _ := disk.Usage(part.Mountpoint)
-		// TODO: assert it doesn't fail
+	// run by Commit, only results that were applied get to add one
+	commits []func() error
+}
 
-		systemDisks[i] = SystemInformationDisk{
-			Label:      part.Device,
-			Mountpoint: part.Mountpoint,
-			Total:      usage.Total,
-			Free:       usage.Free,
-			Used:       usage.Used,
-		}
-	}
+// onCommit defers fn until the information has been stored, e.g. moving a
+// read position past what was collected.
+func (info *SystemInformation) onCommit(fn func() error) {
+	info.commits = append(info.commits, fn)
+}
 
-	ioCounters, _ := net.IOCounters(false)
-	// TODO: assert it doesn't fail
-	networkStats := make([]SystemInformationNetwork, len(ioCounters))
-	for idx, nic := range ioCounters {
-		networkStats[idx] = SystemInformationNetwork{
-			Name:      nic.Name,
-			BytesSent: nic.BytesSent,
-			BytesRecv: nic.BytesRecv,
-		}
+// Commit tells the collectors their results were appended to the outbox.
+// Without it they collect the same data again, as they do when their result
+// was dropped after a timeout.
+func (info *SystemInformation) Commit() error {
+	var errs []error
+	for _, commit := range info.commits {
+		errs = append(errs, commit())
 	}
 
-	return SystemInformation{
-		Timestamp:   time.Now(),
-		TotalMemory: v.Total,
-		FreeMemory:  v.Free,
-		UsedMemory:  v.Used,
-		TotalCPU:    float32(totalCPU),
-		FreeCPU:     float32(freeCPU),
-		UsedCPU:     float32(usedCPU),
-		Disks:       systemDisks,
-		Networks:    networkStats,
-	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,69 @@
+package systeminformation
+
+import (
+	"path/filepath"
+	"slices"
+
+	"github.com/shirou/gopsutil/disk"
+)
+
+// DefaultExcludedFSTypes are pseudo and in-memory filesystems, they don't
+// hold data worth watching and some of them fail disk.Usage.
+var DefaultExcludedFSTypes = []string{
+	"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2", "configfs", "debugfs",
+	"devfs", "devpts", "devtmpfs", "efivarfs", "fdescfs", "fusectl", "hugetlbfs",
+	"mqueue", "nsfs", "nullfs", "overlay", "proc", "procfs", "pstore", "ramfs",
+	"rpc_pipefs", "securityfs", "selinuxfs", "squashfs", "sysfs", "tmpfs",
+	"tracefs",
+}
+
+// DiskFilter decides which partitions are reported. Mountpoints and devices
+// are matched as globs. A partition has to match every non empty include
+// list and none of the exclude lists.
+type DiskFilter struct {
+	IncludeFSTypes     []string
+	ExcludeFSTypes     []string
+	IncludeMountpoints []string
+	ExcludeMountpoints []string
+	IncludeDevices     []string
+	ExcludeDevices     []string
+}
+
+// DefaultDiskFilter drops the virtual filesystems and snap loop mounts.
+func DefaultDiskFilter() DiskFilter {
+	return DiskFilter{
+		ExcludeFSTypes:     DefaultExcludedFSTypes,
+		ExcludeMountpoints: []string{"/snap/*", "/var/lib/snapd/snap/*"},
+	}
+}
+
+// Patterns returns every glob of the filter, to validate them up front.
+func (f DiskFilter) Patterns() []string {
+	return slices.Concat(f.IncludeMountpoints, f.ExcludeMountpoints, f.IncludeDevices, f.ExcludeDevices)
+}
+
+func matchesAny(patterns []string, val string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, val); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f DiskFilter) Match(part disk.PartitionStat) bool {
+	if len(f.IncludeFSTypes) > 0 && !slices.Contains(f.IncludeFSTypes, part.Fstype) {
+		return false
+	}
+	if len(f.IncludeMountpoints) > 0 && !matchesAny(f.IncludeMountpoints, part.Mountpoint) {
+		return false
+	}
+	if len(f.IncludeDevices) > 0 && !matchesAny(f.IncludeDevices, part.Device) {
+		return false
+	}
+
+	return !slices.Contains(f.ExcludeFSTypes, part.Fstype) &&
+		!matchesAny(f.ExcludeMountpoints, part.Mountpoint) &&
+		!matchesAny(f.ExcludeDevices, part.Device)
+}
@@ -0,0 +1,181 @@
+package systeminformation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/host"
+	"github.com/shirou/gopsutil/mem"
+)
+
+// Version is set at build time with
+// -ldflags "-X github.com/microwatcher/agent/internal/systeminformation.Version=v1.2.3",
+// builds without it report the module version or the vcs revision.
+var Version = ""
+
+type InventoryInterface struct {
+	Name      string
+	MAC       string
+	Addresses []string
+}
+
+// Inventory holds the facts about the host that only change on upgrades,
+// reboots or network changes.
+type Inventory struct {
+	Hostname string
+	OS       string
+	// the distribution, e.g. ubuntu
+	Platform        string
+	PlatformFamily  string
+	PlatformVersion string
+	KernelVersion   string
+	Arch            string
+	CPUModel        string
+	CPUCores        int32
+	CPUThreads      int32
+	TotalMemory     uint64
+	BootTime        time.Time
+	Interfaces      []InventoryInterface
+	AgentVersion    string
+	// e.g. kvm or docker, empty on bare metal
+	VirtualizationSystem string
+	// guest or host
+	VirtualizationRole string
+}
+
+// ReadInventory returns what it could read, failing parts are joined in the
+// error.
+func ReadInventory(ctx context.Context) (Inventory, error) {
+	inventory := Inventory{
+		OS:           runtime.GOOS,
+		Arch:         runtime.GOARCH,
+		AgentVersion: agentVersion(),
+	}
+
+	var errs []error
+
+	info, err := host.InfoWithContext(ctx)
+	if err != nil {
+		errs = append(errs, errors.Join(errors.New("failed to read host info"), err))
+	} else {
+		inventory.Hostname = info.Hostname
+		inventory.Platform = info.Platform
+		inventory.PlatformFamily = info.PlatformFamily
+		inventory.PlatformVersion = info.PlatformVersion
+		inventory.KernelVersion = info.KernelVersion
+		inventory.BootTime = time.Unix(int64(info.BootTime), 0).UTC()
+		inventory.VirtualizationSystem = info.VirtualizationSystem
+		inventory.VirtualizationRole = info.VirtualizationRole
+		if info.KernelArch != "" {
+			inventory.Arch = info.KernelArch
+		}
+	}
+
+	cpus, err := cpu.InfoWithContext(ctx)
+	if err != nil {
+		errs = append(errs, errors.Join(errors.New("failed to read cpu info"), err))
+	} else if len(cpus) > 0 {
+		inventory.CPUModel = cpus[0].ModelName
+	}
+
+	cores, err := cpu.CountsWithContext(ctx, false)
+	if err != nil {
+		errs = append(errs, errors.Join(errors.New("failed to count cpu cores"), err))
+	}
+	inventory.CPUCores = int32(cores)
+
+	threads, err := cpu.CountsWithContext(ctx, true)
+	if err != nil {
+		errs = append(errs, errors.Join(errors.New("failed to count cpu threads"), err))
+	}
+	inventory.CPUThreads = int32(threads)
+
+	memory, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		errs = append(errs, errors.Join(errors.New("failed to read memory"), err))
+	} else {
+		inventory.TotalMemory = memory.Total
+	}
+
+	inventory.Interfaces, err = inventoryInterfaces()
+	if err != nil {
+		errs = append(errs, errors.Join(errors.New("failed to list interfaces"), err))
+	}
+
+	return inventory, errors.Join(errs...)
+}
+
+// inventoryInterfaces lists the interfaces that are up with a routable
+// address, so container veths coming and going don't count as changes.
+func inventoryInterfaces() ([]InventoryInterface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var interfaces []InventoryInterface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		var addresses []string
+		for _, addr := range addrs {
+			prefix, ok := addr.(*net.IPNet)
+			if !ok || prefix.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			addresses = append(addresses, prefix.IP.String())
+		}
+		if len(addresses) == 0 {
+			continue
+		}
+
+		slices.Sort(addresses)
+		interfaces = append(interfaces, InventoryInterface{
+			Name:      iface.Name,
+			MAC:       iface.HardwareAddr.String(),
+			Addresses: addresses,
+		})
+	}
+
+	slices.SortFunc(interfaces, func(a, b InventoryInterface) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return interfaces, nil
+}
+
+func agentVersion() string {
+	if Version != "" {
+		return Version
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return "devel-" + setting.Value
+		}
+	}
+
+	return "devel"
+}
@@ -0,0 +1,572 @@
+package systeminformation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	LogFormatRaw   = "raw"
+	LogFormatJSON  = "json"
+	LogFormatRegex = "regex"
+)
+
+var LogFormats = []string{LogFormatRaw, LogFormatJSON, LogFormatRegex}
+
+const (
+	// longer lines are cut, the rest is shipped as the following line
+	maxLogLineBytes = 64 << 10
+	// bounds a single collection, the rest is read on the next one
+	maxLogLinesPerCollect = 10000
+	logReadChunk          = 64 << 10
+)
+
+type SystemInformationLog struct {
+	// the log collector name
+	Source    string
+	Path      string
+	Timestamp time.Time
+	// normalised to debug, info, warn, error or fatal when recognised,
+	// empty when the line doesn't carry one
+	Severity string
+	Message  string
+	Fields   map[string]string
+}
+
+var severityAliases = map[string]string{
+	"trace":    "debug",
+	"debug":    "debug",
+	"dbg":      "debug",
+	"info":     "info",
+	"inf":      "info",
+	"notice":   "info",
+	"warn":     "warn",
+	"warning":  "warn",
+	"wrn":      "warn",
+	"error":    "error",
+	"err":      "error",
+	"fatal":    "fatal",
+	"critical": "fatal",
+	"crit":     "fatal",
+	"panic":    "fatal",
+	"alert":    "fatal",
+	"emerg":    "fatal",
+}
+
+func normaliseSeverity(val string) string {
+	val = strings.ToLower(strings.TrimSpace(val))
+	if alias, ok := severityAliases[val]; ok {
+		return alias
+	}
+
+	return val
+}
+
+// LogParser extracts the severity, message and fields of a line. Lines that
+// don't parse are shipped raw.
+type LogParser struct {
+	format  string
+	pattern *regexp.Regexp
+}
+
+// NewLogParser builds a parser for format, pattern is a regex with named
+// groups for the regex format. The severity, level, message, msg and time
+// groups or keys fill the matching parts of the entry, the others become
+// fields.
+func NewLogParser(format string, pattern string) (LogParser, error) {
+	switch format {
+	case "", LogFormatRaw:
+		return LogParser{format: LogFormatRaw}, nil
+	case LogFormatJSON:
+		return LogParser{format: LogFormatJSON}, nil
+	case LogFormatRegex:
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return LogParser{}, errors.Join(fmt.Errorf("invalid pattern %q", pattern), err)
+		}
+		if slices.IndexFunc(compiled.SubexpNames(), func(name string) bool { return name != "" }) < 0 {
+			return LogParser{}, fmt.Errorf("pattern %q has no named groups", pattern)
+		}
+
+		return LogParser{format: LogFormatRegex, pattern: compiled}, nil
+	default:
+		return LogParser{}, fmt.Errorf("unknown log format %q, expected one of %s", format, strings.Join(LogFormats, ", "))
+	}
+}
+
+// apply sets the entry part named key to val, true when key is one.
+func (entry *SystemInformationLog) apply(key string, val string) bool {
+	switch strings.ToLower(key) {
+	case "severity", "level", "lvl":
+		entry.Severity = normaliseSeverity(val)
+	case "message", "msg":
+		entry.Message = val
+	case "time", "timestamp", "ts":
+		if parsed, err := time.Parse(time.RFC3339Nano, val); err == nil {
+			entry.Timestamp = parsed
+		} else {
+			return false
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (p LogParser) parse(line string, entry *SystemInformationLog) {
+	entry.Message = line
+
+	switch p.format {
+	case LogFormatJSON:
+		var parsed map[string]any
+		if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+			return
+		}
+
+		entry.Fields = make(map[string]string, len(parsed))
+		for key, val := range parsed {
+			var str string
+			switch v := val.(type) {
+			case string:
+				str = v
+			case nil:
+				continue
+			default:
+				encoded, _ := json.Marshal(v)
+				str = string(encoded)
+			}
+
+			if !entry.apply(key, str) {
+				entry.Fields[key] = str
+			}
+		}
+	case LogFormatRegex:
+		match := p.pattern.FindStringSubmatch(line)
+		if match == nil {
+			return
+		}
+
+		entry.Fields = map[string]string{}
+		for idx, name := range p.pattern.SubexpNames() {
+			if name == "" || idx >= len(match) {
+				continue
+			}
+			if !entry.apply(name, match[idx]) {
+				entry.Fields[name] = match[idx]
+			}
+		}
+	}
+}
+
+// logOffset is what is persisted per file to resume after a restart.
+type logOffset struct {
+	ID     uint64 `json:"id"`
+	Offset int64  `json:"offset"`
+}
+
+type logFile struct {
+	path string
+	file *os.File
+	info os.FileInfo
+	// bytes of complete lines read so far
+	offset int64
+	// bytes of complete lines stored in the outbox, reads start over from
+	// here when a collection wasn't committed
+	committed int64
+	partial   []byte
+}
+
+// rewind goes back to the last committed offset.
+func (f *logFile) rewind() {
+	f.offset = f.committed
+	f.partial = nil
+}
+
+// drained reports whether the file was read to its end.
+func (f *logFile) drained() bool {
+	info, err := f.file.Stat()
+	return err == nil && len(f.partial) == 0 && f.offset >= info.Size()
+}
+
+type LogCollector struct {
+	Schedule
+	name          string
+	patterns      []string
+	parser        LogParser
+	fromBeginning bool
+	statePath     string
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+	files   map[string]*logFile
+	// files no longer at their path, e.g. rotated to a name the patterns
+	// don't match, read to their end before letting go of them
+	draining []*logFile
+	// offsets of the files commit let go of since the last collection, by
+	// file id, in case they show up under a name the patterns match
+	released map[uint64]int64
+}
+
+// NewLogCollector tails the files matching the glob patterns, following
+// rotation and truncation. Offsets are persisted to statePath so a restart
+// resumes where it stopped, files seen for the first time start at their
+// end unless fromBeginning is set.
+func NewLogCollector(schedule Schedule, name string, patterns []string, parser LogParser, fromBeginning bool, statePath string) *LogCollector {
+	return &LogCollector{
+		Schedule:      schedule,
+		name:          name,
+		patterns:      patterns,
+		parser:        parser,
+		fromBeginning: fromBeginning,
+		statePath:     statePath,
+		files:         map[string]*logFile{},
+	}
+}
+
+func (c *LogCollector) Name() string {
+	return "logs:" + c.name
+}
+
+func (c *LogCollector) loadOffsets() map[string]logOffset {
+	offsets := map[string]logOffset{}
+
+	data, err := os.ReadFile(c.statePath)
+	if err != nil {
+		return offsets
+	}
+	// a corrupted state file only means starting over
+	_ = json.Unmarshal(data, &offsets)
+
+	return offsets
+}
+
+// saveOffsets persists the committed offsets.
+func (c *LogCollector) saveOffsets() error {
+	offsets := make(map[string]logOffset, len(c.files))
+	for path, tracked := range c.files {
+		id, _ := fileID(tracked.info)
+		offsets[path] = logOffset{ID: id, Offset: tracked.committed}
+	}
+
+	data, err := json.Marshal(offsets)
+	if err != nil {
+		return err
+	}
+
+	return writeState(c.statePath, data)
+}
+
+// writeState writes data next to path and renames it in place, so a crash
+// never leaves a partial file.
+func writeState(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// open starts tailing path. A file already read under another name, as
+// app.log.1 is after a rotation when the patterns match app.log*, carries on
+// from its offset instead of being shipped again.
+func (c *LogCollector) open(path string, offsets map[string]logOffset) (*logFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	for idx, tracked := range c.draining {
+		if os.SameFile(info, tracked.info) {
+			file.Close()
+			c.draining = slices.Delete(c.draining, idx, idx+1)
+			tracked.path = path
+			return tracked, nil
+		}
+	}
+
+	tracked := &logFile{path: path, file: file, info: info}
+	id, hasID := fileID(info)
+	saved, hasSaved := offsets[path]
+	if hasID && (!hasSaved || saved.ID != id) {
+		// renamed while the agent was stopped
+		for _, other := range offsets {
+			if other.ID == id {
+				saved, hasSaved = other, true
+				break
+			}
+		}
+	}
+
+	released, hasReleased := c.released[id]
+	switch {
+	case hasID && hasReleased && released <= info.Size():
+		tracked.offset = released
+	case hasSaved && hasID && saved.ID == id && saved.Offset <= info.Size():
+		tracked.offset = saved.Offset
+	case hasSaved:
+		// rotated or truncated while the agent was stopped
+	case !c.started && !c.fromBeginning:
+		tracked.offset = info.Size()
+	}
+	tracked.committed = tracked.offset
+
+	return tracked, nil
+}
+
+// read ships the complete lines written since the last read, flush ships
+// the trailing partial line as well for files that won't grow anymore.
+func (c *LogCollector) read(path string, tracked *logFile, budget int, flush bool) ([]SystemInformationLog, error) {
+	var entries []SystemInformationLog
+	emit := func(line []byte) {
+		text := strings.TrimSuffix(string(line), "\r")
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+
+		entry := SystemInformationLog{
+			Source:    c.name,
+			Path:      path,
+			Timestamp: time.Now(),
+		}
+		c.parser.parse(text, &entry)
+		entries = append(entries, entry)
+	}
+
+	buf := make([]byte, logReadChunk)
+	for len(entries) < budget {
+		n, err := tracked.file.ReadAt(buf, tracked.offset+int64(len(tracked.partial)))
+		data := append(tracked.partial, buf[:n]...)
+		tracked.partial = nil
+
+		for len(entries) < budget {
+			idx := slices.Index(data, '\n')
+			if idx < 0 {
+				break
+			}
+
+			emit(data[:idx])
+			tracked.offset += int64(idx + 1)
+			data = data[idx+1:]
+		}
+
+		if len(data) > maxLogLineBytes && slices.Index(data, '\n') < 0 {
+			emit(data[:maxLogLineBytes])
+			tracked.offset += maxLogLineBytes
+			data = data[maxLogLineBytes:]
+		}
+		tracked.partial = data
+
+		if errors.Is(err, io.EOF) || n == 0 {
+			break
+		}
+		if err != nil {
+			return entries, err
+		}
+	}
+
+	if flush && len(tracked.partial) > 0 && len(entries) < budget {
+		emit(tracked.partial)
+		tracked.offset += int64(len(tracked.partial))
+		tracked.partial = nil
+	}
+
+	return entries, nil
+}
+
+// Collect reads from the offsets of the last committed collection, the
+// offsets only move on once Commit confirms the lines are in the outbox.
+func (c *LogCollector) Collect(ctx context.Context) (Result, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var offsets map[string]logOffset
+	if !c.started {
+		offsets = c.loadOffsets()
+	}
+
+	var paths []string
+	for _, pattern := range c.patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	slices.Sort(paths)
+	paths = slices.Compact(paths)
+
+	var errs []error
+	var entries []SystemInformationLog
+	budget := func() int {
+		return maxLogLinesPerCollect - len(entries)
+	}
+
+	for _, tracked := range c.draining {
+		tracked.rewind()
+	}
+
+	for path, tracked := range c.files {
+		tracked.rewind()
+
+		if !slices.Contains(paths, path) {
+			c.draining = append(c.draining, tracked)
+			delete(c.files, path)
+			continue
+		}
+
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("failed to stat %s: %w", path, err))
+		case !os.SameFile(info, tracked.info):
+			// rotated, the previous file is finished before the new one
+			c.draining = append(c.draining, tracked)
+			delete(c.files, path)
+		case info.Size() < tracked.committed:
+			// truncated in place, e.g. by copytruncate, it goes unnoticed
+			// when the file grew past the offset again in between
+			tracked.committed = 0
+			tracked.rewind()
+		}
+	}
+
+	// opened before reading the draining files, which they may take over
+	for _, path := range paths {
+		if _, ok := c.files[path]; ok {
+			continue
+		}
+
+		tracked, err := c.open(path, offsets)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to open %s: %w", path, err))
+			continue
+		}
+		c.files[path] = tracked
+	}
+	c.released = nil
+
+	for _, tracked := range c.draining {
+		read, err := c.read(tracked.path, tracked, budget(), true)
+		entries = append(entries, read...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read %s: %w", tracked.path, err))
+		}
+	}
+
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+
+		tracked, ok := c.files[path]
+		if !ok {
+			continue
+		}
+
+		read, err := c.read(path, tracked, budget(), false)
+		entries = append(entries, read...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read %s: %w", path, err))
+		}
+	}
+
+	c.started = true
+
+	// what the commit moves the offsets to, a later collection may already
+	// have read further
+	reached := make(map[*logFile]int64, len(c.files)+len(c.draining))
+	for _, tracked := range c.files {
+		reached[tracked] = tracked.offset
+	}
+	for _, tracked := range c.draining {
+		reached[tracked] = tracked.offset
+	}
+
+	return func(info *SystemInformation) {
+		info.Logs = append(info.Logs, entries...)
+		info.onCommit(func() error {
+			return c.commit(reached)
+		})
+	}, errors.Join(errs...)
+}
+
+// commit moves the offsets past the lines of a stored collection, closing
+// the rotated files it read to their end, and persists them.
+func (c *LogCollector) commit(reached map[*logFile]int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the collector that replaced this one resumes from the saved offsets
+	if c.closed {
+		return nil
+	}
+
+	for tracked, offset := range reached {
+		tracked.committed = offset
+	}
+
+	c.draining = slices.DeleteFunc(c.draining, func(tracked *logFile) bool {
+		if tracked.committed < reached[tracked] || !tracked.drained() {
+			return false
+		}
+
+		if id, ok := fileID(tracked.info); ok {
+			if c.released == nil {
+				c.released = map[uint64]int64{}
+			}
+			c.released[id] = tracked.committed
+		}
+		tracked.file.Close()
+		return true
+	})
+
+	if err := c.saveOffsets(); err != nil {
+		return errors.Join(errors.New("failed to save log offsets"), err)
+	}
+
+	return nil
+}
+
+// Close closes the files being tailed once the collector is replaced, its
+// last uncommitted lines are read again by the new one.
+func (c *LogCollector) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for path, tracked := range c.files {
+		errs = append(errs, tracked.file.Close())
+		delete(c.files, path)
+	}
+	for _, tracked := range c.draining {
+		errs = append(errs, tracked.file.Close())
+	}
+	c.draining = nil
+	c.closed = true
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package systeminformation
+
+import (
+	"os"
+)
+
+// fileID isn't available, offsets are only resumed on unix.
+func fileID(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package systeminformation
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID identifies a file across renames, to tell a rotated log apart from
+// the one that replaced it.
+func fileID(info os.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+
+	return uint64(stat.Ino), true
+}
@@ -0,0 +1,102 @@
+package systeminformation
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/shirou/gopsutil/net"
+)
+
+type SystemInformationNetwork struct {
+	Name              string
+	BytesSent         uint64
+	BytesRecv         uint64
+	PacketsSent       uint64
+	PacketsRecv       uint64
+	ErrorsIn          uint64
+	ErrorsOut         uint64
+	DropsIn           uint64
+	DropsOut          uint64
+	BytesSentPerSec   float64
+	BytesRecvPerSec   float64
+	PacketsSentPerSec float64
+	PacketsRecvPerSec float64
+}
+
+type networkSample struct {
+	at       time.Time
+	counters net.IOCountersStat
+}
+
+// networkDeltas keeps the previous counters per interface to turn them into
+// rates.
+type networkDeltas struct {
+	previous map[string]networkSample
+}
+
+// counterDelta returns how much a counter grew between two samples. A counter
+// that went backwards from close to the 32 bit limit wrapped around, anything
+// else means the interface was reset and there is no meaningful delta.
+func counterDelta(prev uint64, curr uint64) (uint64, bool) {
+	if curr >= prev {
+		return curr - prev, true
+	}
+
+	if prev <= math.MaxUint32 && prev > math.MaxUint32/4*3 {
+		return curr + (math.MaxUint32 - prev) + 1, true
+	}
+
+	return 0, false
+}
+
+func (d *networkDeltas) collect(ctx context.Context) ([]SystemInformationNetwork, error) {
+	ioCounters, err := net.IOCountersWithContext(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	current := make(map[string]networkSample, len(ioCounters))
+	networkStats := make([]SystemInformationNetwork, len(ioCounters))
+	for idx, nic := range ioCounters {
+		current[nic.Name] = networkSample{at: now, counters: nic}
+
+		stat := SystemInformationNetwork{
+			Name:        nic.Name,
+			BytesSent:   nic.BytesSent,
+			BytesRecv:   nic.BytesRecv,
+			PacketsSent: nic.PacketsSent,
+			PacketsRecv: nic.PacketsRecv,
+			ErrorsIn:    nic.Errin,
+			ErrorsOut:   nic.Errout,
+			DropsIn:     nic.Dropin,
+			DropsOut:    nic.Dropout,
+		}
+
+		if prev, ok := d.previous[nic.Name]; ok {
+			elapsed := now.Sub(prev.at).Seconds()
+
+			rate := func(prev uint64, curr uint64) float64 {
+				delta, ok := counterDelta(prev, curr)
+				if !ok || elapsed <= 0 {
+					return 0
+				}
+				return float64(delta) / elapsed
+			}
+
+			stat.BytesSentPerSec = rate(prev.counters.BytesSent, nic.BytesSent)
+			stat.BytesRecvPerSec = rate(prev.counters.BytesRecv, nic.BytesRecv)
+			stat.PacketsSentPerSec = rate(prev.counters.PacketsSent, nic.PacketsSent)
+			stat.PacketsRecvPerSec = rate(prev.counters.PacketsRecv, nic.PacketsRecv)
+		}
+
+		networkStats[idx] = stat
+	}
+
+	// interfaces that went away are forgotten, if they come back their
+	// counters start over anyway
+	d.previous = current
+
+	return networkStats, nil
+}
@@ -0,0 +1,203 @@
+package systeminformation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"slices"
+	"time"
+)
+
+const (
+	ProbeHTTP = "http"
+	ProbeTCP  = "tcp"
+	ProbeDNS  = "dns"
+)
+
+var ProbeKinds = []string{ProbeHTTP, ProbeTCP, ProbeDNS}
+
+// maxProbeBodyBytes bounds how much of a response the body regex sees
+const maxProbeBodyBytes = 1 << 20
+
+// probeSlack is how much longer than the probe the registry waits, so a
+// probe timing out is reported as a failed probe and not dropped
+const probeSlack = time.Second
+
+// ProbeAssertions are checked on http probes, a zero value only requires a
+// 2xx or 3xx status.
+type ProbeAssertions struct {
+	// accepted status codes, any 2xx or 3xx when empty
+	Status []int
+	// matched against the first MB of the body
+	Body *regexp.Regexp
+	// minimum days left before the server certificate expires
+	CertExpiryDays int
+}
+
+type SystemInformationProbe struct {
+	Name      string
+	Kind      string
+	Target    string
+	Timestamp time.Time
+	Success   bool
+	Latency   time.Duration
+	// zero unless an http probe got a response
+	StatusCode int
+	// zero unless the probe went over TLS
+	CertNotAfter time.Time
+	// why the probe failed, empty on success
+	Error string
+}
+
+type ProbeCollector struct {
+	Schedule
+	name       string
+	kind       string
+	target     string
+	assertions ProbeAssertions
+	client     *http.Client
+	dialer     *net.Dialer
+	resolver   *net.Resolver
+}
+
+// NewProbeCollector checks target every interval, the schedule's timeout
+// bounds the probe itself. http targets are URLs, tcp targets host:port
+// and dns targets hostnames.
+func NewProbeCollector(schedule Schedule, name string, kind string, target string, assertions ProbeAssertions) *ProbeCollector {
+	// every probe opens a new connection, so latency includes the handshakes
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = true
+
+	return &ProbeCollector{
+		Schedule:   schedule,
+		name:       name,
+		kind:       kind,
+		target:     target,
+		assertions: assertions,
+		client:     &http.Client{Transport: transport},
+		dialer:     &net.Dialer{},
+		resolver:   net.DefaultResolver,
+	}
+}
+
+func (c *ProbeCollector) Name() string {
+	return "probe:" + c.name
+}
+
+func (c *ProbeCollector) Timeout() time.Duration {
+	return c.Schedule.Timeout() + probeSlack
+}
+
+// Collect never fails, a failed probe is a result like any other.
+func (c *ProbeCollector) Collect(ctx context.Context) (Result, error) {
+	probeCtx, cancel := context.WithTimeout(ctx, c.Schedule.Timeout())
+	defer cancel()
+
+	probe := SystemInformationProbe{
+		Name:      c.name,
+		Kind:      c.kind,
+		Target:    c.target,
+		Timestamp: time.Now(),
+	}
+
+	var err error
+	switch c.kind {
+	case ProbeHTTP:
+		err = c.probeHTTP(probeCtx, &probe)
+	case ProbeTCP:
+		err = c.probeTCP(probeCtx)
+	case ProbeDNS:
+		err = c.probeDNS(probeCtx)
+	default:
+		err = fmt.Errorf("unknown probe kind %q", c.kind)
+	}
+
+	probe.Latency = time.Since(probe.Timestamp)
+	probe.Success = err == nil
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", c.Schedule.Timeout())
+		}
+		probe.Error = err.Error()
+	}
+
+	return func(info *SystemInformation) {
+		info.Probes = append(info.Probes, probe)
+	}, nil
+}
+
+func (c *ProbeCollector) probeHTTP(ctx context.Context, probe *SystemInformationProbe) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "microwatcher-agent")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	probe.StatusCode = resp.StatusCode
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		probe.CertNotAfter = resp.TLS.PeerCertificates[0].NotAfter
+	}
+
+	// read the body even without a regex, latency covers the whole response
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBodyBytes))
+	if err != nil {
+		return errors.Join(errors.New("failed to read body"), err)
+	}
+
+	if len(c.assertions.Status) > 0 {
+		if !slices.Contains(c.assertions.Status, resp.StatusCode) {
+			return fmt.Errorf("unexpected status %s", resp.Status)
+		}
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	if c.assertions.Body != nil && !c.assertions.Body.Match(body) {
+		return fmt.Errorf("body doesn't match %q", c.assertions.Body.String())
+	}
+
+	if c.assertions.CertExpiryDays > 0 {
+		if probe.CertNotAfter.IsZero() {
+			return errors.New("no server certificate to check the expiry of")
+		}
+
+		left := time.Until(probe.CertNotAfter)
+		if left < time.Duration(c.assertions.CertExpiryDays)*24*time.Hour {
+			return fmt.Errorf("certificate expires in %.1f days, expected at least %d", left.Hours()/24, c.assertions.CertExpiryDays)
+		}
+	}
+
+	return nil
+}
+
+func (c *ProbeCollector) probeTCP(ctx context.Context) error {
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.target)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+func (c *ProbeCollector) probeDNS(ctx context.Context) error {
+	addrs, err := c.resolver.LookupHost(ctx, c.target)
+	if err != nil {
+		return err
+	}
+
+	if len(addrs) == 0 {
+		return fmt.Errorf("%s resolved to no addresses", c.target)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package systeminformation
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func runProbe(t *testing.T, c *ProbeCollector) SystemInformationProbe {
+	t.Helper()
+
+	result, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	var info SystemInformation
+	result(&info)
+	if len(info.Probes) != 1 {
+		t.Fatalf("expected 1 probe result, got %d", len(info.Probes))
+	}
+
+	return info.Probes[0]
+}
+
+func newTestProbe(kind string, target string, timeout time.Duration, assertions ProbeAssertions) *ProbeCollector {
+	return NewProbeCollector(NewSchedule(time.Minute, timeout), "test", kind, target, assertions)
+}
+
+func TestProbeHTTPStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	probe := runProbe(t, newTestProbe(ProbeHTTP, srv.URL, time.Second, ProbeAssertions{}))
+	if probe.Success {
+		t.Error("expected a 503 to fail without status assertions")
+	}
+	if probe.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", probe.StatusCode)
+	}
+
+	probe = runProbe(t, newTestProbe(ProbeHTTP, srv.URL, time.Second, ProbeAssertions{
+		Status: []int{http.StatusServiceUnavailable},
+	}))
+	if !probe.Success {
+		t.Errorf("expected an accepted 503 to succeed, got %q", probe.Error)
+	}
+}
+
+func TestProbeHTTPBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	probe := runProbe(t, newTestProbe(ProbeHTTP, srv.URL, time.Second, ProbeAssertions{
+		Body: regexp.MustCompile(`"status":"ok"`),
+	}))
+	if !probe.Success {
+		t.Errorf("expected a matching body to succeed, got %q", probe.Error)
+	}
+
+	probe = runProbe(t, newTestProbe(ProbeHTTP, srv.URL, time.Second, ProbeAssertions{
+		Body: regexp.MustCompile(`"status":"degraded"`),
+	}))
+	if probe.Success {
+		t.Error("expected a body that doesn't match to fail")
+	}
+}
+
+func TestProbeHTTPCertExpiry(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	left := time.Until(srv.Certificate().NotAfter)
+	days := int(left.Hours() / 24)
+
+	c := newTestProbe(ProbeHTTP, srv.URL, time.Second, ProbeAssertions{CertExpiryDays: days - 1})
+	c.client = srv.Client()
+	probe := runProbe(t, c)
+	if !probe.Success {
+		t.Errorf("expected a certificate valid for %d days to succeed, got %q", days, probe.Error)
+	}
+	if !probe.CertNotAfter.Equal(srv.Certificate().NotAfter) {
+		t.Errorf("expected cert not after %s, got %s", srv.Certificate().NotAfter, probe.CertNotAfter)
+	}
+
+	c = newTestProbe(ProbeHTTP, srv.URL, time.Second, ProbeAssertions{CertExpiryDays: days + 1})
+	c.client = srv.Client()
+	probe = runProbe(t, c)
+	if probe.Success {
+		t.Error("expected a certificate expiring too soon to fail")
+	}
+}
+
+func TestProbeHTTPTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	probe := runProbe(t, newTestProbe(ProbeHTTP, srv.URL, 50*time.Millisecond, ProbeAssertions{}))
+	if probe.Success {
+		t.Fatal("expected a slow response to fail")
+	}
+	if !strings.HasPrefix(probe.Error, "timed out") {
+		t.Errorf("expected a timeout error, got %q", probe.Error)
+	}
+}
+
+func TestProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+
+	probe := runProbe(t, newTestProbe(ProbeTCP, addr, time.Second, ProbeAssertions{}))
+	if !probe.Success {
+		t.Errorf("expected an open port to succeed, got %q", probe.Error)
+	}
+
+	listener.Close()
+
+	probe = runProbe(t, newTestProbe(ProbeTCP, addr, time.Second, ProbeAssertions{}))
+	if probe.Success {
+		t.Error("expected a closed port to fail")
+	}
+}
+
+func TestProbeDNS(t *testing.T) {
+	probe := runProbe(t, newTestProbe(ProbeDNS, "localhost", time.Second, ProbeAssertions{}))
+	if !probe.Success {
+		t.Errorf("expected localhost to resolve, got %q", probe.Error)
+	}
+}
@@ -0,0 +1,131 @@
+package systeminformation
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"time"
+
+	"github.com/shirou/gopsutil/process"
+)
+
+type SystemInformationProcess struct {
+	PID         int32
+	Name        string
+	CmdlineHash string
+	User        string
+	CPUPercent  float64
+	RSS         uint64
+	Threads     int32
+	OpenFDs     int32
+}
+
+type processSample struct {
+	at         time.Time
+	createTime int64
+	cpuTime    float64
+}
+
+// processDeltas keeps the previous cpu time per process, a pid is only
+// matched with its previous sample when the create time is the same so a
+// reused pid starts over.
+type processDeltas struct {
+	previous map[int32]processSample
+}
+
+// hashCmdline identifies a command line without shipping its arguments,
+// which regularly contain secrets.
+func hashCmdline(cmdline string) string {
+	sum := sha256.Sum256([]byte(cmdline))
+	return hex.EncodeToString(sum[:8])
+}
+
+type processRank struct {
+	proc       *process.Process
+	cpuPercent float64
+	rss        uint64
+}
+
+// top returns the top n processes by cpu usage since the previous call and
+// the top n by resident memory, a process in both lists is reported once.
+// Processes that exit while being sampled are skipped.
+func (d *processDeltas) top(ctx context.Context, n int) ([]SystemInformationProcess, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	current := make(map[int32]processSample, len(procs))
+	ranks := make([]processRank, 0, len(procs))
+	for _, proc := range procs {
+		createTime, err := proc.CreateTimeWithContext(ctx)
+		if err != nil {
+			continue
+		}
+
+		times, err := proc.TimesWithContext(ctx)
+		if err != nil {
+			continue
+		}
+
+		memory, err := proc.MemoryInfoWithContext(ctx)
+		if err != nil {
+			continue
+		}
+
+		cpuTime := times.User + times.System
+		current[proc.Pid] = processSample{at: now, createTime: createTime, cpuTime: cpuTime}
+
+		rank := processRank{proc: proc, rss: memory.RSS}
+		if prev, ok := d.previous[proc.Pid]; ok && prev.createTime == createTime {
+			if elapsed := now.Sub(prev.at).Seconds(); elapsed > 0 {
+				rank.cpuPercent = max(cpuTime-prev.cpuTime, 0) / elapsed * 100
+			}
+		}
+
+		ranks = append(ranks, rank)
+	}
+
+	d.previous = current
+
+	slices.SortFunc(ranks, func(a, b processRank) int {
+		return cmp.Compare(b.cpuPercent, a.cpuPercent)
+	})
+	selected := slices.Clone(ranks[:min(n, len(ranks))])
+
+	slices.SortFunc(ranks, func(a, b processRank) int {
+		return cmp.Compare(b.rss, a.rss)
+	})
+	for _, rank := range ranks[:min(n, len(ranks))] {
+		if !slices.ContainsFunc(selected, func(r processRank) bool { return r.proc.Pid == rank.proc.Pid }) {
+			selected = append(selected, rank)
+		}
+	}
+
+	processes := make([]SystemInformationProcess, 0, len(selected))
+	for _, rank := range selected {
+		// the details are only read for the reported processes, errors leave
+		// them empty since the process may have exited in the meantime
+		name, _ := rank.proc.NameWithContext(ctx)
+		cmdline, _ := rank.proc.CmdlineWithContext(ctx)
+		user, _ := rank.proc.UsernameWithContext(ctx)
+		threads, _ := rank.proc.NumThreadsWithContext(ctx)
+		openFDs, _ := rank.proc.NumFDsWithContext(ctx)
+
+		processes = append(processes, SystemInformationProcess{
+			PID:         rank.proc.Pid,
+			Name:        name,
+			CmdlineHash: hashCmdline(cmdline),
+			User:        user,
+			CPUPercent:  rank.cpuPercent,
+			RSS:         rank.rss,
+			Threads:     threads,
+			OpenFDs:     openFDs,
+		})
+	}
+
+	return processes, nil
+}
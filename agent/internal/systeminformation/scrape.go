@@ -0,0 +1,118 @@
+package systeminformation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/microwatcher/agent/internal/metrics"
+)
+
+// maxScrapeBytes bounds how much of a response is parsed, a runaway
+// endpoint shouldn't take the agent's memory with it.
+const maxScrapeBytes = 32 << 20
+
+const scrapeAccept = "text/plain;version=0.0.4;q=1,*/*;q=0.1"
+
+type ScrapeCollector struct {
+	Schedule
+	name     string
+	url      string
+	instance string
+	filter   metrics.Filter
+	relabel  []metrics.RelabelRule
+	client   *http.Client
+}
+
+// NewScrapeCollector scrapes a Prometheus endpoint, every metric gets the
+// job and instance labels before relabeling, as Prometheus does.
+func NewScrapeCollector(schedule Schedule, name string, target string, filter metrics.Filter, relabel []metrics.RelabelRule) *ScrapeCollector {
+	instance := target
+	if parsed, err := url.Parse(target); err == nil {
+		instance = parsed.Host
+	}
+
+	return &ScrapeCollector{
+		Schedule: schedule,
+		name:     name,
+		url:      target,
+		instance: instance,
+		filter:   filter,
+		relabel:  relabel,
+		client:   &http.Client{},
+	}
+}
+
+func (c *ScrapeCollector) Name() string {
+	return "scrape:" + c.name
+}
+
+// Collect always reports the up metric, so a failing endpoint shows up as a
+// series and not only as a collector error.
+func (c *ScrapeCollector) Collect(ctx context.Context) (Result, error) {
+	scraped, err := c.scrape(ctx)
+
+	up := 1.0
+	if err != nil {
+		up = 0
+	}
+	scraped = append(scraped, metrics.Metric{Name: "up", Type: metrics.TypeGauge, Value: up})
+
+	kept := make([]metrics.Metric, 0, len(scraped))
+	for _, metric := range scraped {
+		if metric.Name != "up" && !c.filter.Match(metric.Name) {
+			continue
+		}
+
+		if metric.Labels == nil {
+			metric.Labels = map[string]string{}
+		}
+		if _, ok := metric.Labels["job"]; !ok {
+			metric.Labels["job"] = c.name
+		}
+		if _, ok := metric.Labels["instance"]; !ok {
+			metric.Labels["instance"] = c.instance
+		}
+
+		metric, ok := metrics.Relabel(metric, c.relabel)
+		if !ok {
+			continue
+		}
+
+		metric.Source = c.name
+		kept = append(kept, metric)
+	}
+
+	return func(info *SystemInformation) {
+		info.Metrics = append(info.Metrics, kept...)
+	}, err
+}
+
+func (c *ScrapeCollector) scrape(ctx context.Context) ([]metrics.Metric, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", scrapeAccept)
+	req.Header.Set("User-Agent", "microwatcher-agent")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to scrape"), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("scrape returned %s", resp.Status)
+	}
+
+	parsed, err := metrics.ParsePrometheus(io.LimitReader(resp.Body, maxScrapeBytes))
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to parse scrape"), err)
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,17 @@
+package systeminformation
+
+const (
+	SensorTemperature = "temperature"
+	SensorFan         = "fan"
+)
+
+// SystemInformationSensor is a temperature in celsius or a fan speed in rpm,
+// thresholds the hardware doesn't expose are left at zero.
+type SystemInformationSensor struct {
+	Kind     string
+	Chip     string
+	Label    string
+	Value    float64
+	High     float64
+	Critical float64
+}
@@ -0,0 +1,192 @@
+package systeminformation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// sysPath honours HOST_SYS like gopsutil does, so a containerized agent can
+// read the host's sysfs.
+func sysPath(elem ...string) string {
+	root := os.Getenv("HOST_SYS")
+	if root == "" {
+		root = "/sys"
+	}
+
+	return filepath.Join(append([]string{root}, elem...)...)
+}
+
+func readTrimmed(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readMilli reads a sysfs value expressed in thousandths, zero when missing.
+func readMilli(path string) float64 {
+	val, err := readTrimmed(path)
+	if err != nil {
+		return 0
+	}
+
+	parsed, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0
+	}
+
+	return parsed / 1000
+}
+
+// hwmonSensors reads every tempN and fanN input of every hwmon chip, older
+// drivers put them under device/.
+func hwmonSensors() ([]SystemInformationSensor, error) {
+	chips, err := filepath.Glob(sysPath("class", "hwmon", "hwmon*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sensors []SystemInformationSensor
+	var errs []error
+	for _, chip := range chips {
+		name, err := readTrimmed(filepath.Join(chip, "name"))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		dir := chip
+		inputs, _ := filepath.Glob(filepath.Join(dir, "*_input"))
+		if len(inputs) == 0 {
+			dir = filepath.Join(chip, "device")
+			inputs, _ = filepath.Glob(filepath.Join(dir, "*_input"))
+		}
+
+		for _, input := range inputs {
+			prefix := strings.TrimSuffix(filepath.Base(input), "_input")
+
+			label, err := readTrimmed(filepath.Join(dir, prefix+"_label"))
+			if err != nil {
+				label = prefix
+			}
+
+			raw, err := readTrimmed(input)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			switch {
+			case strings.HasPrefix(prefix, "temp"):
+				sensors = append(sensors, SystemInformationSensor{
+					Kind:     SensorTemperature,
+					Chip:     name,
+					Label:    label,
+					Value:    value / 1000,
+					High:     readMilli(filepath.Join(dir, prefix+"_max")),
+					Critical: readMilli(filepath.Join(dir, prefix+"_crit")),
+				})
+			case strings.HasPrefix(prefix, "fan"):
+				sensors = append(sensors, SystemInformationSensor{
+					Kind:  SensorFan,
+					Chip:  name,
+					Label: label,
+					Value: value,
+				})
+			}
+		}
+	}
+
+	return sensors, errors.Join(errs...)
+}
+
+// thermalZoneSensors covers boards without hwmon, like most single board
+// computers, using the hot and critical trip points as thresholds.
+func thermalZoneSensors() ([]SystemInformationSensor, error) {
+	zones, err := filepath.Glob(sysPath("class", "thermal", "thermal_zone*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sensors []SystemInformationSensor
+	var errs []error
+	for _, zone := range zones {
+		kind, err := readTrimmed(filepath.Join(zone, "type"))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		raw, err := readTrimmed(filepath.Join(zone, "temp"))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		sensor := SystemInformationSensor{
+			Kind:  SensorTemperature,
+			Chip:  "thermal",
+			Label: kind,
+			Value: value / 1000,
+		}
+
+		trips, _ := filepath.Glob(filepath.Join(zone, "trip_point_*_type"))
+		for _, trip := range trips {
+			tripType, err := readTrimmed(trip)
+			if err != nil {
+				continue
+			}
+
+			temp := readMilli(strings.TrimSuffix(trip, "_type") + "_temp")
+			switch tripType {
+			case "hot":
+				sensor.High = temp
+			case "critical":
+				sensor.Critical = temp
+			}
+		}
+
+		sensors = append(sensors, sensor)
+	}
+
+	return sensors, errors.Join(errs...)
+}
+
+func collectSensors(_ context.Context) ([]SystemInformationSensor, error) {
+	sensors, err := hwmonSensors()
+	if err != nil && len(sensors) == 0 {
+		return nil, err
+	}
+
+	hasTemperature := false
+	for _, sensor := range sensors {
+		if sensor.Kind == SensorTemperature {
+			hasTemperature = true
+			break
+		}
+	}
+
+	if !hasTemperature {
+		zones, zoneErr := thermalZoneSensors()
+		sensors = append(sensors, zones...)
+		err = errors.Join(err, zoneErr)
+	}
+
+	return sensors, err
+}
@@ -0,0 +1,34 @@
+//go:build !linux
+
+package systeminformation
+
+import (
+	"context"
+	"strings"
+
+	"github.com/shirou/gopsutil/host"
+)
+
+// collectSensors falls back to gopsutil outside linux, it reports every
+// reading of a chip as its own key and has no thresholds.
+func collectSensors(ctx context.Context) ([]SystemInformationSensor, error) {
+	temperatures, err := host.SensorsTemperaturesWithContext(ctx)
+	if len(temperatures) == 0 {
+		return nil, err
+	}
+
+	sensors := make([]SystemInformationSensor, 0, len(temperatures))
+	for _, temperature := range temperatures {
+		key := strings.TrimSuffix(temperature.SensorKey, "_input")
+		chip, label, _ := strings.Cut(key, "_")
+
+		sensors = append(sensors, SystemInformationSensor{
+			Kind:  SensorTemperature,
+			Chip:  chip,
+			Label: label,
+			Value: temperature.Temperature,
+		})
+	}
+
+	return sensors, nil
+}
@@ -0,0 +1,45 @@
+package systeminformation
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/host"
+	"github.com/shirou/gopsutil/load"
+)
+
+type SystemInformationSystem struct {
+	Load1        float64
+	Load5        float64
+	Load15       float64
+	Uptime       uint64
+	ProcsTotal   uint64
+	ProcsRunning uint64
+	ProcsBlocked uint64
+}
+
+func collectSystem(ctx context.Context) (*SystemInformationSystem, error) {
+	avg, err := load.AvgWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	misc, err := load.MiscWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uptime, err := host.UptimeWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SystemInformationSystem{
+		Load1:        avg.Load1,
+		Load5:        avg.Load5,
+		Load15:       avg.Load15,
+		Uptime:       uptime,
+		ProcsTotal:   uint64(max(misc.ProcsTotal, 0)),
+		ProcsRunning: uint64(max(misc.ProcsRunning, 0)),
+		ProcsBlocked: uint64(max(misc.ProcsBlocked, 0)),
+	}, nil
+}
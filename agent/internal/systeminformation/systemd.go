@@ -0,0 +1,303 @@
+package systeminformation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bounds the journal entries shipped per collection, the rest follow on
+// the next one
+const maxJournalEntries = 5000
+
+type SystemInformationUnit struct {
+	Name        string
+	LoadState   string
+	ActiveState string
+	SubState    string
+	Restarts    uint32
+	MainPID     int32
+}
+
+// JournalPriorities are the syslog priorities journalctl filters on, from
+// the most to the least severe.
+var JournalPriorities = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// journalSeverity maps a syslog priority onto the log severities.
+func journalSeverity(priority string) string {
+	switch priority {
+	case "0", "1", "2":
+		return "fatal"
+	case "3":
+		return "error"
+	case "4":
+		return "warn"
+	case "5", "6":
+		return "info"
+	case "7":
+		return "debug"
+	default:
+		return ""
+	}
+}
+
+// parseSystemctlShow parses the blank line separated blocks systemctl show
+// prints for several units.
+func parseSystemctlShow(out []byte) []SystemInformationUnit {
+	var units []SystemInformationUnit
+	for _, block := range bytes.Split(out, []byte("\n\n")) {
+		props := map[string]string{}
+		for _, line := range strings.Split(string(block), "\n") {
+			if key, val, ok := strings.Cut(line, "="); ok {
+				props[key] = val
+			}
+		}
+		if props["Id"] == "" {
+			continue
+		}
+
+		unit := SystemInformationUnit{
+			Name:        props["Id"],
+			LoadState:   props["LoadState"],
+			ActiveState: props["ActiveState"],
+			SubState:    props["SubState"],
+		}
+		if restarts, err := strconv.ParseUint(props["NRestarts"], 10, 32); err == nil {
+			unit.Restarts = uint32(restarts)
+		}
+		if pid, err := strconv.ParseInt(props["MainPID"], 10, 32); err == nil {
+			unit.MainPID = int32(pid)
+		}
+
+		units = append(units, unit)
+	}
+
+	return units
+}
+
+type SystemdCollector struct {
+	Schedule
+	units []string
+	// empty disables forwarding the journal
+	journalPriority string
+
+	// where the committed cursor is kept across restarts and reloads
+	statePath string
+
+	// where the last stored journal entries end, guarded by mu as commits
+	// come from the loop storing the results
+	mu     sync.Mutex
+	loaded bool
+	cursor string
+	since  time.Time
+}
+
+// NewSystemdCollector reports the state of units through systemctl and,
+// with a journalPriority, forwards their journal entries up to that
+// priority as logs. They resume from the cursor saved at statePath, or
+// start from the creation of the collector.
+func NewSystemdCollector(schedule Schedule, units []string, journalPriority string, statePath string) *SystemdCollector {
+	return &SystemdCollector{
+		Schedule:        schedule,
+		units:           units,
+		journalPriority: journalPriority,
+		statePath:       statePath,
+		since:           time.Now(),
+	}
+}
+
+// loadCursor reads the saved cursor on the first read of the journal, not
+// in the constructor, so a collector replacing this one on a reload sees
+// what the last commit saved.
+func (c *SystemdCollector) loadCursor() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+
+	data, err := os.ReadFile(c.statePath)
+	if err != nil {
+		return
+	}
+	c.cursor = strings.TrimSpace(string(data))
+}
+
+// commit moves the cursor past stored entries and persists it.
+func (c *SystemdCollector) commit(cursor string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cursor = cursor
+	if err := writeState(c.statePath, []byte(cursor)); err != nil {
+		return errors.Join(errors.New("failed to save journal cursor"), err)
+	}
+
+	return nil
+}
+
+func (c *SystemdCollector) Name() string {
+	return "systemd"
+}
+
+func (c *SystemdCollector) states(ctx context.Context) ([]SystemInformationUnit, error) {
+	args := append([]string{"show", "--property=Id,LoadState,ActiveState,SubState,NRestarts,MainPID", "--"}, c.units...)
+
+	out, err := exec.CommandContext(ctx, "systemctl", args...).Output()
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to run systemctl show"), err)
+	}
+
+	return parseSystemctlShow(out), nil
+}
+
+type journalEntry struct {
+	Cursor     string `json:"__CURSOR"`
+	Realtime   string `json:"__REALTIME_TIMESTAMP"`
+	Unit       string `json:"_SYSTEMD_UNIT"`
+	Priority   string `json:"PRIORITY"`
+	Identifier string `json:"SYSLOG_IDENTIFIER"`
+	PID        string `json:"_PID"`
+	// a string, or an array of bytes when the message isn't valid utf-8
+	Message json.RawMessage `json:"MESSAGE"`
+}
+
+func (entry journalEntry) message() string {
+	var text string
+	if err := json.Unmarshal(entry.Message, &text); err == nil {
+		return text
+	}
+
+	var raw []byte
+	var ints []int
+	if err := json.Unmarshal(entry.Message, &ints); err == nil {
+		raw = make([]byte, len(ints))
+		for idx, val := range ints {
+			raw[idx] = byte(val)
+		}
+	}
+
+	return strings.ToValidUTF8(string(raw), "�")
+}
+
+// journal reads the entries written after the committed cursor, without one
+// from the creation of the collector, and returns the cursor of the last one.
+func (c *SystemdCollector) journal(ctx context.Context) ([]SystemInformationLog, string, error) {
+	c.mu.Lock()
+	c.loadCursor()
+	cursor := c.cursor
+	c.mu.Unlock()
+
+	args := []string{"--output=json", "--no-pager", "--priority=" + c.journalPriority}
+	for _, unit := range c.units {
+		args = append(args, "--unit="+unit)
+	}
+	if cursor != "" {
+		args = append(args, "--after-cursor="+cursor)
+	} else {
+		args = append(args, "--since=@"+strconv.FormatInt(c.since.Unix(), 10))
+	}
+
+	readCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(readCtx, "journalctl", args...)
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, cursor, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, cursor, errors.Join(errors.New("failed to run journalctl"), err)
+	}
+
+	var entries []SystemInformationLog
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLogLineBytes*4)
+	for len(entries) < maxJournalEntries && scanner.Scan() {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		cursor = entry.Cursor
+
+		timestamp := time.Now()
+		if usec, err := strconv.ParseInt(entry.Realtime, 10, 64); err == nil {
+			timestamp = time.UnixMicro(usec)
+		}
+
+		entries = append(entries, SystemInformationLog{
+			Source:    "journal",
+			Path:      entry.Unit,
+			Timestamp: timestamp,
+			Severity:  journalSeverity(entry.Priority),
+			Message:   entry.message(),
+			Fields: map[string]string{
+				"priority":          entry.Priority,
+				"syslog_identifier": entry.Identifier,
+				"pid":               entry.PID,
+			},
+		})
+	}
+	scanErr := scanner.Err()
+
+	// past the limit journalctl is stopped, the cursor resumes after the
+	// last entry read
+	stopped := len(entries) >= maxJournalEntries
+	if stopped || scanErr != nil {
+		cancel()
+	}
+	waitErr := cmd.Wait()
+
+	switch {
+	case scanErr != nil:
+		return entries, cursor, errors.Join(errors.New("failed to read journal"), scanErr)
+	case waitErr != nil && !stopped:
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > maxStderr {
+			msg = msg[:maxStderr]
+		}
+		return entries, cursor, errors.Join(fmt.Errorf("journalctl failed: %s", msg), waitErr)
+	}
+
+	return entries, cursor, nil
+}
+
+func (c *SystemdCollector) Collect(ctx context.Context) (Result, error) {
+	var errs []error
+
+	units, err := c.states(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	var entries []SystemInformationLog
+	var cursor string
+	if c.journalPriority != "" {
+		entries, cursor, err = c.journal(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to forward journal: %w", err))
+		}
+	}
+
+	return func(info *SystemInformation) {
+		info.Units = units
+		info.Logs = append(info.Logs, entries...)
+		if cursor == "" {
+			return
+		}
+		// until then the same entries are read again
+		info.onCommit(func() error {
+			return c.commit(cursor)
+		})
+	}, errors.Join(errs...)
+}
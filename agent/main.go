@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"github.com/alecthomas/kong"
 	"github.com/microwatcher/agent/internal/cli"
@@ -30,17 +31,59 @@ func main() {
 		}),
 	)
 
-	agentConfig := config.
-		NewConfig(jsonLogger)
-
 	switch kongCtx.Command() {
 	case "check":
-		agentConfig.ApplyCheckOverrides(cliArgs.Check)
+		agentConfig, err := config.LoadCheck(jsonLogger, cliArgs.Config, cliArgs.Check)
+		if err != nil {
+			jsonLogger.Error("invalid config", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+
 		start.Ping(ctx, agentConfig)
 	case "start":
-		agentConfig.ApplyStartOverrides(cliArgs.Start)
-		start.Start(ctx, agentConfig)
+		agentConfig, err := config.LoadStart(jsonLogger, cliArgs.Config, cliArgs.Start)
+		if err != nil {
+			jsonLogger.Error("invalid config", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+
+		start.Start(ctx, agentConfig, reloadOnHangup(ctx, jsonLogger, cliArgs))
 	default:
 		panic(kongCtx.Command())
 	}
 }
+
+// reloadOnHangup rebuilds the start config on every SIGHUP, an invalid config
+// is logged and the agent keeps running with the previous one.
+func reloadOnHangup(ctx context.Context, logger *slog.Logger, cliArgs cli.CLI) <-chan *config.Config {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+
+	reloads := make(chan *config.Config)
+	go func() {
+		defer signal.Stop(hangups)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangups:
+				logger.Info("reloading config", slog.String("path", cliArgs.Config))
+
+				agentConfig, err := config.LoadStart(logger, cliArgs.Config, cliArgs.Start)
+				if err != nil {
+					logger.Error("invalid config, keeping the current one", slog.String("error", err.Error()))
+					continue
+				}
+
+				select {
+				case reloads <- agentConfig:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return reloads
+}
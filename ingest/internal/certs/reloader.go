@@ -0,0 +1,170 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// checkEvery throttles how often the files are stat'ed for changes
+const checkEvery = time.Second * 10
+
+// Reloader serves a TLS config built from certificate files on disk and
+// rebuilds it whenever one of the files changes, so certificates can be
+// rotated without restarting the server.
+type Reloader struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	Logger       *slog.Logger
+
+	mu        sync.Mutex
+	config    *tls.Config
+	modTimes  map[string]time.Time
+	checkedAt time.Time
+}
+
+func NewReloader(certFile, keyFile, clientCAFile string, logger *slog.Logger) (*Reloader, error) {
+	r := &Reloader{
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		ClientCAFile: clientCAFile,
+		Logger:       logger,
+	}
+
+	config, modTimes, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	r.config = config
+	r.modTimes = modTimes
+	r.checkedAt = time.Now()
+
+	return r, nil
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.CertFile, r.KeyFile}
+	if r.ClientCAFile != "" {
+		files = append(files, r.ClientCAFile)
+	}
+	return files
+}
+
+func (r *Reloader) load() (*tls.Config, map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, nil, errors.Join(fmt.Errorf("failed to stat %s", file), err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.CertFile, r.KeyFile)
+	if err != nil {
+		return nil, nil, errors.Join(errors.New("failed to load key pair"), err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.NoClientCert,
+		NextProtos:   []string{"h2"},
+	}
+
+	if r.ClientCAFile != "" {
+		pemBytes, err := os.ReadFile(r.ClientCAFile)
+		if err != nil {
+			return nil, nil, errors.Join(errors.New("failed to read client ca"), err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemBytes) {
+			return nil, nil, fmt.Errorf("no certificates found in %s", r.ClientCAFile)
+		}
+
+		// client certificates are optional, devices can still authenticate
+		// with their HMAC signature
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return config, modTimes, nil
+}
+
+func (r *Reloader) changed() bool {
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false
+		}
+		if !info.ModTime().Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reloader) current() *tls.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) < checkEvery {
+		return r.config
+	}
+	r.checkedAt = time.Now()
+
+	if !r.changed() {
+		return r.config
+	}
+
+	config, modTimes, err := r.load()
+	if err != nil {
+		// keep serving the previous certificates, the files may be mid-write
+		r.Logger.Error("failed to reload certificates",
+			slog.String("error", err.Error()),
+		)
+		return r.config
+	}
+
+	r.Logger.Info("reloaded certificates",
+		slog.String("cert", r.CertFile),
+		slog.String("clientCA", r.ClientCAFile),
+	)
+	r.config = config
+	r.modTimes = modTimes
+
+	return r.config
+}
+
+// Config returns the TLS config to hand to the server, every handshake picks
+// up the latest certificates.
+func (r *Reloader) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2"},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current(), nil
+		},
+	}
+}
+
+// HTTPConfig is Config for the HTTP receivers, which also speak HTTP/1.1.
+func (r *Reloader) HTTPConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			config := r.current().Clone()
+			config.NextProtos = []string{"h2", "http/1.1"}
+			return config, nil
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package receiver
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/microwatcher/ingest/internal"
+	"github.com/samborkent/uuidv7"
+	"google.golang.org/protobuf/proto"
+)
+
+// APIKeys maps API keys to the device their data is stored under, for
+// clients like Prometheus which can't be configured with a device secret.
+type APIKeys map[string]string
+
+// ParseAPIKeys parses a comma separated list of key:device-id pairs.
+func ParseAPIKeys(val string) (APIKeys, error) {
+	keys := APIKeys{}
+	for _, pair := range strings.Split(val, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, deviceID, ok := strings.Cut(pair, ":")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("api key entry %q should be key:device-id", pair)
+		}
+		if !uuidv7.IsValidString(deviceID) {
+			return nil, fmt.Errorf("api key entry has an invalid device id %q", deviceID)
+		}
+
+		keys[key] = deviceID
+	}
+
+	return keys, nil
+}
+
+// Device returns the device of key, comparing every key in constant time.
+func (keys APIKeys) Device(key string) (string, bool) {
+	var found string
+	for candidate, deviceID := range keys {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			found = deviceID
+		}
+	}
+
+	return found, found != ""
+}
+
+// bearerToken returns the API key of a request, InfluxDB clients send it as
+// "Token <key>".
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return token, true
+	}
+
+	return strings.CutPrefix(header, "Token ")
+}
+
+// authenticateKey resolves the device an API key maps to. Like the device
+// secret, keys are only accepted over TLS.
+func authenticateKey(ctx context.Context, server *internal.Server, keys APIKeys, r *http.Request, key string) (string, error) {
+	if r.TLS == nil {
+		server.Logger.Error("api key sent without TLS")
+		return "", errors.New("api keys are only accepted over TLS")
+	}
+
+	deviceID, ok := keys.Device(key)
+	if !ok {
+		server.Logger.Error("invalid api key")
+		return "", errors.New("invalid api key")
+	}
+
+	if _, err := server.Clickhouse.FindDeviceByID(ctx, deviceID); err != nil {
+		server.Logger.Error("failed to find client",
+			slog.String("error", err.Error()),
+		)
+		return "", errors.Join(errors.New("failed to find client"), err)
+	}
+
+	return deviceID, nil
+}
+
+// authenticate resolves the device of an HTTP push, from a bearer API key
+// or, like the grpc services, from a certificate, the device secret or a
+// signature of msg.
+func authenticate(ctx context.Context, server *internal.Server, keys APIKeys, r *http.Request, msg proto.Message) (string, error) {
+	if token, ok := bearerToken(r); ok {
+		return authenticateKey(ctx, server, keys, r, token)
+	}
+
+	return server.AuthenticateReceiver(ctx, msg)
+}
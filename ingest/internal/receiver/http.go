@@ -0,0 +1,76 @@
+package receiver
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// MaxBodyBytes bounds the decompressed size of a pushed request.
+const MaxBodyBytes = 16 << 20
+
+var errBodyTooLarge = errors.New("request body too large")
+
+// authHeaders are the headers carried over to the grpc metadata the device
+// authentication reads.
+var authHeaders = []string{"x-client-id", "x-signature", "x-client-secret"}
+
+// incomingContext exposes an HTTP request like a grpc call, so HTTP
+// receivers authenticate devices the same way, client certificates
+// included. Basic auth is read as the client id and secret, which are only
+// accepted over TLS.
+func incomingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for _, header := range authHeaders {
+		if value := r.Header.Get(header); value != "" {
+			md.Set(header, value)
+		}
+	}
+	if user, password, ok := r.BasicAuth(); ok && len(md.Get("x-client-id")) == 0 {
+		md.Set("x-client-id", user)
+		md.Set("x-client-secret", password)
+	}
+
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	if r.TLS != nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{
+			AuthInfo: credentials.TLSInfo{State: *r.TLS},
+		})
+	}
+
+	return ctx
+}
+
+// readBody reads the request body, decompressing gzip, up to MaxBodyBytes.
+func readBody(r *http.Request) ([]byte, error) {
+	var body io.Reader = r.Body
+	switch r.Header.Get("Content-Encoding") {
+	case "", "identity":
+	case "gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, errors.Join(errors.New("invalid gzip body"), err)
+		}
+		defer gz.Close()
+		body = gz
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", r.Header.Get("Content-Encoding"))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, MaxBodyBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxBodyBytes {
+		return nil, errBodyTooLarge
+	}
+
+	return data, nil
+}
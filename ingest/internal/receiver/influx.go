@@ -0,0 +1,316 @@
+package receiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/microwatcher/ingest/internal"
+	"github.com/microwatcher/shared/pkg/clickhouse"
+	v2 "github.com/microwatcher/shared/pkg/gen/microwatcher/v2"
+	"github.com/microwatcher/shared/pkg/otlp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultInfluxIdentifier is stored for points without a host tag.
+const DefaultInfluxIdentifier = "influx"
+
+var influxPrecisions = map[string]time.Duration{
+	"":   time.Nanosecond,
+	"n":  time.Nanosecond,
+	"ns": time.Nanosecond,
+	"u":  time.Microsecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+type influxField struct {
+	key   string
+	value float64
+}
+
+type influxPoint struct {
+	measurement string
+	tags        map[string]string
+	fields      []influxField
+	// zero when the line has none, the time of the write is used
+	timestamp time.Time
+}
+
+// cutUnescaped cuts s around the first sep that isn't escaped with a
+// backslash nor, with quotes, inside a double quoted string.
+func cutUnescaped(s string, sep byte, quotes bool) (string, string, bool) {
+	quoted := false
+	for idx := 0; idx < len(s); idx++ {
+		switch {
+		case s[idx] == '\\':
+			idx++
+		case quotes && s[idx] == '"':
+			quoted = !quoted
+		case s[idx] == sep && !quoted:
+			return s[:idx], s[idx+1:], true
+		}
+	}
+
+	return s, "", false
+}
+
+func splitUnescaped(s string, sep byte, quotes bool) []string {
+	var parts []string
+	for {
+		part, rest, ok := cutUnescaped(s, sep, quotes)
+		parts = append(parts, part)
+		if !ok {
+			return parts
+		}
+		s = rest
+	}
+}
+
+var influxUnescaper = strings.NewReplacer(`\,`, ",", `\=`, "=", `\ `, " ", `\"`, `"`, `\\`, `\`)
+
+// parseInfluxValue parses a field value, strings can't be stored as a
+// metric and are reported as not ok.
+func parseInfluxValue(val string) (float64, bool, error) {
+	switch val {
+	case "t", "T", "true", "True", "TRUE":
+		return 1, true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return 0, true, nil
+	}
+
+	if strings.HasPrefix(val, `"`) {
+		return 0, false, nil
+	}
+
+	if num, ok := strings.CutSuffix(val, "i"); ok {
+		parsed, err := strconv.ParseInt(num, 10, 64)
+		return float64(parsed), true, err
+	}
+	if num, ok := strings.CutSuffix(val, "u"); ok {
+		parsed, err := strconv.ParseUint(num, 10, 64)
+		return float64(parsed), true, err
+	}
+
+	parsed, err := strconv.ParseFloat(val, 64)
+	if err == nil && (math.IsNaN(parsed) || math.IsInf(parsed, 0)) {
+		err = fmt.Errorf("invalid float %q", val)
+	}
+
+	return parsed, true, err
+}
+
+// parseLineProtocol parses InfluxDB line protocol, timestamps are in units
+// of precision.
+func parseLineProtocol(data []byte, precision time.Duration) ([]influxPoint, error) {
+	var points []influxPoint
+	for lineNum, line := range bytes.Split(data, []byte("\n")) {
+		text := strings.TrimSpace(string(line))
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, rest, ok := cutUnescaped(text, ' ', false)
+		if !ok {
+			return nil, fmt.Errorf("line %d has no fields", lineNum+1)
+		}
+		fieldSet, timestamp, _ := cutUnescaped(rest, ' ', true)
+
+		keyParts := splitUnescaped(key, ',', false)
+		point := influxPoint{
+			measurement: influxUnescaper.Replace(keyParts[0]),
+			tags:        make(map[string]string, len(keyParts)-1),
+		}
+		if point.measurement == "" {
+			return nil, fmt.Errorf("line %d has no measurement", lineNum+1)
+		}
+
+		for _, tag := range keyParts[1:] {
+			name, val, ok := cutUnescaped(tag, '=', false)
+			if !ok || name == "" {
+				return nil, fmt.Errorf("line %d has an invalid tag %q", lineNum+1, tag)
+			}
+			point.tags[influxUnescaper.Replace(name)] = influxUnescaper.Replace(val)
+		}
+
+		for _, field := range splitUnescaped(fieldSet, ',', true) {
+			name, val, ok := cutUnescaped(field, '=', true)
+			if !ok || name == "" || val == "" {
+				return nil, fmt.Errorf("line %d has an invalid field %q", lineNum+1, field)
+			}
+
+			value, numeric, err := parseInfluxValue(val)
+			if err != nil {
+				return nil, errors.Join(fmt.Errorf("line %d has an invalid value for %s", lineNum+1, name), err)
+			}
+			if !numeric {
+				continue
+			}
+
+			point.fields = append(point.fields, influxField{key: influxUnescaper.Replace(name), value: value})
+		}
+
+		if timestamp = strings.TrimSpace(timestamp); timestamp != "" {
+			parsed, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				return nil, errors.Join(fmt.Errorf("line %d has an invalid timestamp", lineNum+1), err)
+			}
+			// past year 2262 in nanoseconds, it would wrap around
+			if parsed > math.MaxInt64/int64(precision) || parsed < math.MinInt64/int64(precision) {
+				return nil, fmt.Errorf("line %d has a timestamp out of range", lineNum+1)
+			}
+			point.timestamp = time.Unix(0, parsed*int64(precision))
+		}
+
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// fromInfluxPoints maps every numeric field onto a gauge named
+// <measurement>_<field>, or just the measurement for a field called value,
+// grouped by the host tag as identifier.
+func fromInfluxPoints(points []influxPoint, now time.Time) map[string][]*v2.Metric {
+	grouped := map[string][]*v2.Metric{}
+	for _, point := range points {
+		identifier := point.tags["host"]
+		if identifier == "" {
+			identifier = DefaultInfluxIdentifier
+		}
+
+		timestamp := point.timestamp
+		if timestamp.IsZero() {
+			timestamp = now
+		}
+
+		for _, field := range point.fields {
+			name := point.measurement
+			if field.key != "value" {
+				name += "_" + field.key
+			}
+
+			grouped[identifier] = append(grouped[identifier], &v2.Metric{
+				Timestamp: timestamppb.New(timestamp),
+				Name:      name,
+				Type:      v2.MetricType_METRIC_TYPE_GAUGE,
+				Labels:    point.tags,
+				Data:      &v2.Metric_Value{Value: field.value},
+			})
+		}
+	}
+
+	return grouped
+}
+
+// Influx receives InfluxDB line protocol on the v1 /write and v2
+// /api/v2/write endpoints. Clients authenticate with an API key of the
+// listener, as a token, the p parameter or the basic auth password.
+type Influx struct {
+	Server  *internal.Server
+	APIKeys APIKeys
+}
+
+// Handler routes the endpoints InfluxDB clients expect.
+func (rcv *Influx) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/write", rcv.write)
+	mux.HandleFunc("/api/v2/write", rcv.write)
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return mux
+}
+
+func influxError(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"code": code, "message": message})
+}
+
+func (rcv *Influx) write(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otlp.IngestTracer.Start(r.Context(), "Influx.write")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		influxError(w, http.StatusMethodNotAllowed, "method not allowed", "expected POST")
+		return
+	}
+
+	key, ok := bearerToken(r)
+	if !ok {
+		key = r.URL.Query().Get("p")
+	}
+	if key == "" {
+		_, key, _ = r.BasicAuth()
+	}
+
+	deviceID, err := authenticateKey(spanCtx, rcv.Server, rcv.APIKeys, r, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		influxError(w, http.StatusUnauthorized, "unauthorized", "unauthorized access")
+		return
+	}
+
+	precision, ok := influxPrecisions[r.URL.Query().Get("precision")]
+	if !ok {
+		influxError(w, http.StatusBadRequest, "invalid", "unknown precision")
+		return
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to read body")
+
+		status := http.StatusBadRequest
+		if errors.Is(err, errBodyTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		influxError(w, status, "invalid", err.Error())
+		return
+	}
+
+	points, err := parseLineProtocol(body, precision)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid line protocol")
+		influxError(w, http.StatusBadRequest, "invalid", err.Error())
+		return
+	}
+
+	span.SetAttributes(attribute.Int("batch size", len(points)))
+
+	for identifier, metrics := range fromInfluxPoints(points, time.Now()) {
+		if err := rcv.Server.Clickhouse.IngestV2Metrics(spanCtx, deviceID, identifier, metrics); err != nil {
+			rcv.Server.Logger.Error("failed to ingest line protocol",
+				slog.String("error", err.Error()),
+			)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to ingest metrics")
+			if errors.Is(err, clickhouse.ErrInvalidMetric) {
+				influxError(w, http.StatusBadRequest, "invalid", err.Error())
+				return
+			}
+			influxError(w, http.StatusServiceUnavailable, "unavailable", "failed to ingest metrics")
+			return
+		}
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+	w.WriteHeader(http.StatusNoContent)
+}
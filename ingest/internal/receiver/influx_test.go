@@ -0,0 +1,126 @@
+package receiver
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLineProtocol(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []influxPoint
+	}{
+		{
+			name: "fields and timestamp",
+			line: "cpu,host=web-1 usage=1.5,cores=8i,free=3u,up=t 1700000000000000000",
+			want: []influxPoint{{
+				measurement: "cpu",
+				tags:        map[string]string{"host": "web-1"},
+				fields: []influxField{
+					{key: "usage", value: 1.5},
+					{key: "cores", value: 8},
+					{key: "free", value: 3},
+					{key: "up", value: 1},
+				},
+				timestamp: time.Unix(1700000000, 0),
+			}},
+		},
+		{
+			name: "without timestamp",
+			line: "mem used=2",
+			want: []influxPoint{{
+				measurement: "mem",
+				tags:        map[string]string{},
+				fields:      []influxField{{key: "used", value: 2}},
+			}},
+		},
+		{
+			name: "escapes",
+			line: `disk\ io,path=C:\\data,dev\=x=sd\,a read\ bytes=1`,
+			want: []influxPoint{{
+				measurement: "disk io",
+				tags:        map[string]string{"path": `C:\data`, "dev=x": "sd,a"},
+				fields:      []influxField{{key: "read bytes", value: 1}},
+			}},
+		},
+		{
+			name: "quoted field with spaces and commas",
+			line: `log,host=web-1 msg="a b, c=d \"e\"",count=3i 1700000000000000000`,
+			want: []influxPoint{{
+				measurement: "log",
+				tags:        map[string]string{"host": "web-1"},
+				fields:      []influxField{{key: "count", value: 3}},
+				timestamp:   time.Unix(1700000000, 0),
+			}},
+		},
+		{
+			name: "comments and blank lines",
+			line: "# written by telegraf\n\nload value=0.5\n",
+			want: []influxPoint{{
+				measurement: "load",
+				tags:        map[string]string{},
+				fields:      []influxField{{key: "value", value: 0.5}},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseLineProtocol([]byte(test.line), time.Nanosecond)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseLineProtocolPrecision(t *testing.T) {
+	for name, precision := range influxPrecisions {
+		t.Run("precision "+name, func(t *testing.T) {
+			got, err := parseLineProtocol([]byte("cpu usage=1 1700"), precision)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			if want := time.Unix(0, 1700*int64(precision)); !got[0].timestamp.Equal(want) {
+				t.Errorf("got %s, want %s", got[0].timestamp, want)
+			}
+		})
+	}
+}
+
+func TestParseLineProtocolErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		precision time.Duration
+	}{
+		{name: "no fields", line: "cpu"},
+		{name: "no measurement", line: ",host=a usage=1"},
+		{name: "invalid tag", line: "cpu,host usage=1"},
+		{name: "invalid field", line: "cpu usage"},
+		{name: "invalid value", line: "cpu usage=abc"},
+		{name: "nan", line: "cpu usage=NaN"},
+		{name: "invalid timestamp", line: "cpu usage=1 yesterday"},
+		{name: "timestamp overflowing seconds", line: "cpu usage=1 9999999999999", precision: time.Second},
+		{name: "timestamp underflowing hours", line: "cpu usage=1 -9999999999", precision: time.Hour},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			precision := test.precision
+			if precision == 0 {
+				precision = time.Nanosecond
+			}
+
+			if _, err := parseLineProtocol([]byte(test.line), precision); err == nil {
+				t.Errorf("expected %q to fail", test.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,380 @@
+package receiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/microwatcher/ingest/internal"
+	"github.com/microwatcher/shared/pkg/clickhouse"
+	v2 "github.com/microwatcher/shared/pkg/gen/microwatcher/v2"
+	"github.com/microwatcher/shared/pkg/otlp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultOTLPIdentifier is stored for metrics whose resource has neither a
+// host.name nor a service.name.
+const DefaultOTLPIdentifier = "otlp"
+
+// OTLP receives OpenTelemetry metrics and stores them as v2 metrics,
+// resource and scope attributes become labels.
+type OTLP struct {
+	Server *internal.Server
+	// accepted as bearer tokens over HTTP
+	APIKeys APIKeys
+	colmetricspb.UnimplementedMetricsServiceServer
+}
+
+func (rcv *OTLP) Export(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "OTLP.Export",
+		trace.WithAttributes(attribute.String("method", "Export")),
+		trace.WithAttributes(attribute.Int("batch size", len(req.ResourceMetrics))),
+	)
+	defer span.End()
+
+	deviceID, err := rcv.Server.AuthenticateReceiver(spanCtx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	response, err := rcv.ingest(spanCtx, deviceID, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest metrics")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return response, nil
+}
+
+func (rcv *OTLP) ingest(ctx context.Context, deviceID string, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	var rejected int64
+	var reasons []string
+	reject := func(reason string) {
+		rejected++
+		if !slices.Contains(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	for _, resourceMetrics := range req.ResourceMetrics {
+		identifier, metrics, skipped := fromResourceMetrics(resourceMetrics)
+		for range skipped {
+			reject("exponential histograms are not supported")
+		}
+
+		// dropped here, one bad point would fail the whole batch
+		metrics = slices.DeleteFunc(metrics, func(metric *v2.Metric) bool {
+			err := clickhouse.ValidateV2Metric(metric)
+			if err != nil {
+				reject(err.Error())
+			}
+			return err != nil
+		})
+
+		if len(metrics) == 0 {
+			continue
+		}
+
+		if err := rcv.Server.Clickhouse.IngestV2Metrics(ctx, deviceID, identifier, metrics); err != nil {
+			rcv.Server.Logger.Error("failed to ingest otlp metrics",
+				slog.String("error", err.Error()),
+			)
+			return nil, fmt.Errorf("failed to ingest metrics")
+		}
+	}
+
+	response := &colmetricspb.ExportMetricsServiceResponse{}
+	if rejected > 0 {
+		response.PartialSuccess = &colmetricspb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: rejected,
+			ErrorMessage:       strings.Join(reasons, "; "),
+		}
+	}
+
+	return response, nil
+}
+
+// attributeString flattens an attribute value into a label value, arrays
+// and maps keep the shape of their string form.
+func attributeString(value *commonpb.AnyValue) string {
+	switch v := value.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return strconv.FormatInt(v.IntValue, 10)
+	case *commonpb.AnyValue_DoubleValue:
+		return strconv.FormatFloat(v.DoubleValue, 'g', -1, 64)
+	case *commonpb.AnyValue_BytesValue:
+		return fmt.Sprintf("%x", v.BytesValue)
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]string, len(v.ArrayValue.GetValues()))
+		for idx, item := range v.ArrayValue.GetValues() {
+			values[idx] = attributeString(item)
+		}
+		return "[" + strings.Join(values, ",") + "]"
+	case *commonpb.AnyValue_KvlistValue:
+		values := make([]string, len(v.KvlistValue.GetValues()))
+		for idx, item := range v.KvlistValue.GetValues() {
+			values[idx] = item.GetKey() + "=" + attributeString(item.GetValue())
+		}
+		return "{" + strings.Join(values, ",") + "}"
+	default:
+		return ""
+	}
+}
+
+// withAttributes copies labels and adds the attributes, data point
+// attributes win over the resource's.
+func withAttributes(labels map[string]string, attributes []*commonpb.KeyValue) map[string]string {
+	merged := make(map[string]string, len(labels)+len(attributes))
+	for k, v := range labels {
+		merged[k] = v
+	}
+	for _, attr := range attributes {
+		merged[attr.GetKey()] = attributeString(attr.GetValue())
+	}
+
+	return merged
+}
+
+func fromResourceMetrics(resourceMetrics *metricspb.ResourceMetrics) (string, []*v2.Metric, int64) {
+	resourceLabels := withAttributes(nil, resourceMetrics.GetResource().GetAttributes())
+
+	identifier := DefaultOTLPIdentifier
+	if host, ok := resourceLabels["host.name"]; ok && host != "" {
+		identifier = host
+	} else if service, ok := resourceLabels["service.name"]; ok && service != "" {
+		identifier = service
+	}
+
+	var metrics []*v2.Metric
+	var rejected int64
+	for _, scopeMetrics := range resourceMetrics.GetScopeMetrics() {
+		scopeLabels := resourceLabels
+		if scope := scopeMetrics.GetScope(); scope.GetName() != "" {
+			scopeLabels = withAttributes(resourceLabels, scope.GetAttributes())
+			scopeLabels["otel.scope.name"] = scope.GetName()
+		}
+
+		for _, metric := range scopeMetrics.GetMetrics() {
+			converted, skipped := fromMetric(metric, scopeLabels)
+			metrics = append(metrics, converted...)
+			rejected += skipped
+		}
+	}
+
+	return identifier, metrics, rejected
+}
+
+func noRecordedValue(flags uint32) bool {
+	return flags&uint32(metricspb.DataPointFlags_DATA_POINT_FLAGS_NO_RECORDED_VALUE_MASK) != 0
+}
+
+func timestampOf(unixNano uint64) *timestamppb.Timestamp {
+	if unixNano == 0 {
+		return timestamppb.Now()
+	}
+
+	return timestamppb.New(time.Unix(0, int64(unixNano)))
+}
+
+func numberValue(point *metricspb.NumberDataPoint) float64 {
+	if _, ok := point.GetValue().(*metricspb.NumberDataPoint_AsInt); ok {
+		return float64(point.GetAsInt())
+	}
+
+	return point.GetAsDouble()
+}
+
+// fromMetric maps one OTLP metric onto v2 metrics. Only cumulative
+// monotonic sums are counters, delta and non monotonic sums are stored as
+// gauges of what they reported. Summaries are flattened the way Prometheus
+// exposes them, exponential histograms are rejected.
+func fromMetric(metric *metricspb.Metric, labels map[string]string) ([]*v2.Metric, int64) {
+	var metrics []*v2.Metric
+
+	number := func(kind v2.MetricType, name string, point *metricspb.NumberDataPoint) {
+		if noRecordedValue(point.GetFlags()) {
+			return
+		}
+
+		metrics = append(metrics, &v2.Metric{
+			Timestamp: timestampOf(point.GetTimeUnixNano()),
+			Name:      name,
+			Type:      kind,
+			Unit:      metric.GetUnit(),
+			Labels:    withAttributes(labels, point.GetAttributes()),
+			Data:      &v2.Metric_Value{Value: numberValue(point)},
+		})
+	}
+
+	switch data := metric.GetData().(type) {
+	case *metricspb.Metric_Gauge:
+		for _, point := range data.Gauge.GetDataPoints() {
+			number(v2.MetricType_METRIC_TYPE_GAUGE, metric.GetName(), point)
+		}
+	case *metricspb.Metric_Sum:
+		kind := v2.MetricType_METRIC_TYPE_GAUGE
+		if data.Sum.GetIsMonotonic() && data.Sum.GetAggregationTemporality() == metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE {
+			kind = v2.MetricType_METRIC_TYPE_COUNTER
+		}
+		for _, point := range data.Sum.GetDataPoints() {
+			number(kind, metric.GetName(), point)
+		}
+	case *metricspb.Metric_Histogram:
+		for _, point := range data.Histogram.GetDataPoints() {
+			if noRecordedValue(point.GetFlags()) {
+				continue
+			}
+
+			// otlp counts are per bucket, v2 buckets are cumulative
+			buckets := make([]*v2.HistogramBucket, 0, len(point.GetBucketCounts()))
+			var cumulative uint64
+			for idx, count := range point.GetBucketCounts() {
+				cumulative += count
+
+				bound := math.Inf(1)
+				if idx < len(point.GetExplicitBounds()) {
+					bound = point.GetExplicitBounds()[idx]
+				}
+				buckets = append(buckets, &v2.HistogramBucket{UpperBound: bound, Count: cumulative})
+			}
+
+			metrics = append(metrics, &v2.Metric{
+				Timestamp: timestampOf(point.GetTimeUnixNano()),
+				Name:      metric.GetName(),
+				Type:      v2.MetricType_METRIC_TYPE_HISTOGRAM,
+				Unit:      metric.GetUnit(),
+				Labels:    withAttributes(labels, point.GetAttributes()),
+				Data: &v2.Metric_Histogram{Histogram: &v2.Histogram{
+					Buckets: buckets,
+					Sum:     point.GetSum(),
+					Count:   point.GetCount(),
+				}},
+			})
+		}
+	case *metricspb.Metric_Summary:
+		for _, point := range data.Summary.GetDataPoints() {
+			if noRecordedValue(point.GetFlags()) {
+				continue
+			}
+
+			timestamp := timestampOf(point.GetTimeUnixNano())
+			pointLabels := withAttributes(labels, point.GetAttributes())
+			sample := func(kind v2.MetricType, name string, value float64, labels map[string]string) *v2.Metric {
+				return &v2.Metric{
+					Timestamp: timestamp,
+					Name:      name,
+					Type:      kind,
+					Unit:      metric.GetUnit(),
+					Labels:    labels,
+					Data:      &v2.Metric_Value{Value: value},
+				}
+			}
+
+			metrics = append(metrics,
+				sample(v2.MetricType_METRIC_TYPE_COUNTER, metric.GetName()+"_sum", point.GetSum(), pointLabels),
+				sample(v2.MetricType_METRIC_TYPE_COUNTER, metric.GetName()+"_count", float64(point.GetCount()), pointLabels),
+			)
+			for _, quantile := range point.GetQuantileValues() {
+				quantileLabels := withAttributes(pointLabels, nil)
+				quantileLabels["quantile"] = strconv.FormatFloat(quantile.GetQuantile(), 'g', -1, 64)
+				metrics = append(metrics, sample(v2.MetricType_METRIC_TYPE_GAUGE, metric.GetName(), quantile.GetValue(), quantileLabels))
+			}
+		}
+	case *metricspb.Metric_ExponentialHistogram:
+		return nil, int64(len(data.ExponentialHistogram.GetDataPoints()))
+	}
+
+	return metrics, 0
+}
+
+// ServeHTTP implements OTLP/HTTP with protobuf payloads, served on
+// /v1/metrics.
+func (rcv *OTLP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := incomingContext(r)
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "OTLP.ServeHTTP")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/x-protobuf" {
+		http.Error(w, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to read body")
+
+		status := http.StatusBadRequest
+		if errors.Is(err, errBodyTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	var req colmetricspb.ExportMetricsServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid payload")
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	deviceID, err := authenticate(spanCtx, rcv.Server, rcv.APIKeys, r, &req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		http.Error(w, "unauthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	response, err := rcv.ingest(spanCtx, deviceID, &req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest metrics")
+		// otlp clients retry on 503
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	payload, err := proto.Marshal(response)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to marshal response")
+		http.Error(w, "failed to marshal response", http.StatusInternalServerError)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(payload)
+}
@@ -0,0 +1,44 @@
+package receiver
+
+import (
+	"context"
+	"testing"
+
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+func TestOTLPRejectsInvalidMetrics(t *testing.T) {
+	conn := &fakeConn{}
+	rcv := &OTLP{Server: newTestServer(conn)}
+
+	gauge := func(name string) *metricspb.Metric {
+		return &metricspb.Metric{
+			Name: name,
+			Data: &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{
+				DataPoints: []*metricspb.NumberDataPoint{{
+					TimeUnixNano: 1700000000000000000,
+					Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: 1},
+				}},
+			}},
+		}
+	}
+
+	response, err := rcv.ingest(context.Background(), testDeviceID, &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Metrics: []*metricspb.Metric{gauge("queue_depth"), gauge("")},
+			}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("expected the valid metric to be stored, got %v", err)
+	}
+
+	if len(conn.rows) != 1 || conn.rows[0][3] != "queue_depth" {
+		t.Errorf("expected only queue_depth to be stored, got %v", conn.rows)
+	}
+	if rejected := response.GetPartialSuccess().GetRejectedDataPoints(); rejected != 1 {
+		t.Errorf("expected 1 rejected data point, got %d", rejected)
+	}
+}
@@ -0,0 +1,236 @@
+package receiver
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/microwatcher/ingest/internal"
+	"github.com/microwatcher/shared/pkg/clickhouse"
+	v2 "github.com/microwatcher/shared/pkg/gen/microwatcher/v2"
+	prometheusv1 "github.com/microwatcher/shared/pkg/gen/prometheus/v1"
+	"github.com/microwatcher/shared/pkg/otlp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultRemoteWriteInflight is how many writes are stored at once before
+// Prometheus is told to back off.
+const DefaultRemoteWriteInflight = 8
+
+// DefaultRemoteWriteIdentifier is stored for series without an instance
+// label.
+const DefaultRemoteWriteIdentifier = "remote_write"
+
+// staleNaN is the value Prometheus writes to mark a series as gone.
+const staleNaN uint64 = 0x7ff0000000000002
+
+// RemoteWrite receives Prometheus remote write 1.0 requests and stores the
+// samples as v2 metrics.
+type RemoteWrite struct {
+	Server  *internal.Server
+	APIKeys APIKeys
+	// writes stored at once, requests past it get a 429 so Prometheus
+	// retries them later instead of piling up here
+	inflight chan struct{}
+}
+
+func NewRemoteWrite(server *internal.Server, keys APIKeys, inflight int) *RemoteWrite {
+	if inflight <= 0 {
+		inflight = DefaultRemoteWriteInflight
+	}
+
+	return &RemoteWrite{
+		Server:   server,
+		APIKeys:  keys,
+		inflight: make(chan struct{}, inflight),
+	}
+}
+
+func (rcv *RemoteWrite) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := incomingContext(r)
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "RemoteWrite.ServeHTTP")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if encoding := r.Header.Get("Content-Encoding"); encoding != "snappy" {
+		http.Error(w, "expected a snappy encoded body", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	// read as is, readBody only decodes the content encodings of the other
+	// receivers
+	compressed, err := io.ReadAll(io.LimitReader(r.Body, MaxBodyBytes+1))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to read body")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(compressed) > MaxBodyBytes {
+		span.SetStatus(codes.Error, "failed to read body")
+		http.Error(w, errBodyTooLarge.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// remote write uses the snappy block format, which s2 decodes
+	decodedLen, err := s2.DecodedLen(compressed)
+	if err != nil || decodedLen > MaxBodyBytes {
+		http.Error(w, "invalid snappy body", http.StatusBadRequest)
+		return
+	}
+	body, err := s2.Decode(nil, compressed)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid snappy body")
+		http.Error(w, "invalid snappy body", http.StatusBadRequest)
+		return
+	}
+
+	var req prometheusv1.WriteRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid payload")
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	span.SetAttributes(attribute.Int("batch size", len(req.Timeseries)))
+
+	deviceID, err := authenticate(spanCtx, rcv.Server, rcv.APIKeys, r, &req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		http.Error(w, "unauthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	// only storing takes a slot, slow or unauthenticated clients can't
+	// hold them while their body trickles in
+	select {
+	case rcv.inflight <- struct{}{}:
+		defer func() { <-rcv.inflight }()
+	default:
+		span.SetStatus(codes.Error, "write queue saturated")
+		http.Error(w, "write queue saturated", http.StatusTooManyRequests)
+		return
+	}
+
+	for identifier, metrics := range fromWriteRequest(&req) {
+		if err := rcv.Server.Clickhouse.IngestV2Metrics(spanCtx, deviceID, identifier, metrics); err != nil {
+			rcv.Server.Logger.Error("failed to ingest remote write",
+				slog.String("error", err.Error()),
+			)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to ingest metrics")
+			// prometheus retries 5xx, but drops 4xx
+			if errors.Is(err, clickhouse.ErrInvalidMetric) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, "failed to ingest metrics", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// remoteWriteType picks the type of a series from the request's metadata,
+// falling back to the naming conventions as Prometheus usually sends
+// metadata in separate requests.
+func remoteWriteType(name string, types map[string]prometheusv1.MetricMetadata_MetricType) v2.MetricType {
+	family := name
+	for _, suffix := range []string{"_bucket", "_count", "_sum", "_total"} {
+		if trimmed, ok := strings.CutSuffix(name, suffix); ok {
+			family = trimmed
+			break
+		}
+	}
+
+	metaType, ok := types[name]
+	if !ok {
+		metaType = types[family]
+	}
+
+	switch metaType {
+	case prometheusv1.MetricMetadata_METRIC_TYPE_COUNTER:
+		return v2.MetricType_METRIC_TYPE_COUNTER
+	case prometheusv1.MetricMetadata_METRIC_TYPE_GAUGE:
+		return v2.MetricType_METRIC_TYPE_GAUGE
+	case prometheusv1.MetricMetadata_METRIC_TYPE_HISTOGRAM, prometheusv1.MetricMetadata_METRIC_TYPE_SUMMARY:
+		// the quantiles of a summary are gauges, the rest are counters
+		if family == name {
+			return v2.MetricType_METRIC_TYPE_GAUGE
+		}
+		return v2.MetricType_METRIC_TYPE_COUNTER
+	}
+
+	if family != name {
+		return v2.MetricType_METRIC_TYPE_COUNTER
+	}
+	return v2.MetricType_METRIC_TYPE_GAUGE
+}
+
+// fromWriteRequest maps every sample onto a v2 metric, grouped by the
+// instance label as identifier. Stale markers are dropped.
+func fromWriteRequest(req *prometheusv1.WriteRequest) map[string][]*v2.Metric {
+	types := make(map[string]prometheusv1.MetricMetadata_MetricType, len(req.Metadata))
+	units := make(map[string]string, len(req.Metadata))
+	for _, metadata := range req.Metadata {
+		types[metadata.MetricFamilyName] = metadata.Type
+		units[metadata.MetricFamilyName] = metadata.Unit
+	}
+
+	grouped := map[string][]*v2.Metric{}
+	for _, series := range req.Timeseries {
+		var name string
+		labels := make(map[string]string, len(series.Labels))
+		for _, label := range series.Labels {
+			if label.Name == "__name__" {
+				name = label.Value
+				continue
+			}
+			labels[label.Name] = label.Value
+		}
+		if name == "" {
+			continue
+		}
+
+		identifier := labels["instance"]
+		if identifier == "" {
+			identifier = DefaultRemoteWriteIdentifier
+		}
+
+		kind := remoteWriteType(name, types)
+		unit := units[name]
+		for _, sample := range series.Samples {
+			if math.Float64bits(sample.Value) == staleNaN {
+				continue
+			}
+
+			grouped[identifier] = append(grouped[identifier], &v2.Metric{
+				Timestamp: timestamppb.New(time.UnixMilli(sample.Timestamp)),
+				Name:      name,
+				Type:      kind,
+				Unit:      unit,
+				Labels:    labels,
+				Data:      &v2.Metric_Value{Value: sample.Value},
+			})
+		}
+	}
+
+	return grouped
+}
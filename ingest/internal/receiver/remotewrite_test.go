@@ -0,0 +1,146 @@
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/klauspost/compress/s2"
+	"github.com/microwatcher/ingest/internal"
+	"github.com/microwatcher/shared/pkg/clickhouse"
+	prometheusv1 "github.com/microwatcher/shared/pkg/gen/prometheus/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const testDeviceID = "01890a5d-ac96-774b-bcce-b302099a8057"
+
+// fakeConn finds every device and keeps the rows of the batches sent.
+type fakeConn struct {
+	driver.Conn
+
+	mu   sync.Mutex
+	rows [][]any
+}
+
+func (c *fakeConn) QueryRow(ctx context.Context, query string, args ...any) driver.Row {
+	return fakeRow{}
+}
+
+func (c *fakeConn) PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error) {
+	return &fakeBatch{conn: c}, nil
+}
+
+type fakeRow struct {
+	driver.Row
+}
+
+func (fakeRow) ScanStruct(dest any) error {
+	return nil
+}
+
+type fakeBatch struct {
+	driver.Batch
+
+	conn *fakeConn
+	rows [][]any
+}
+
+func (b *fakeBatch) Append(v ...any) error {
+	b.rows = append(b.rows, v)
+	return nil
+}
+
+func (b *fakeBatch) Send() error {
+	b.conn.mu.Lock()
+	defer b.conn.mu.Unlock()
+
+	b.conn.rows = append(b.conn.rows, b.rows...)
+	return nil
+}
+
+func (b *fakeBatch) Close() error {
+	return nil
+}
+
+func newTestServer(conn *fakeConn) *internal.Server {
+	logger := slog.New(slog.DiscardHandler)
+
+	return &internal.Server{
+		Logger:     logger,
+		Clickhouse: &clickhouse.ClickhouseSource{Conn: conn, Logger: logger},
+	}
+}
+
+func TestRemoteWrite(t *testing.T) {
+	conn := &fakeConn{}
+	rcv := NewRemoteWrite(newTestServer(conn), APIKeys{"key": testDeviceID}, 1)
+
+	payload, err := proto.Marshal(&prometheusv1.WriteRequest{
+		Timeseries: []*prometheusv1.TimeSeries{{
+			Labels: []*prometheusv1.Label{
+				{Name: "__name__", Value: "http_requests_total"},
+				{Name: "instance", Value: "web-1"},
+				{Name: "code", Value: "200"},
+			},
+			Samples: []*prometheusv1.Sample{
+				{Value: 42, Timestamp: 1700000000000},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal write request: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "https://ingest/api/v1/write", bytes.NewReader(s2.EncodeSnappy(nil, payload)))
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Authorization", "Bearer key")
+
+	rec := httptest.NewRecorder()
+	rcv.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if len(conn.rows) != 1 {
+		t.Fatalf("expected 1 stored metric, got %d", len(conn.rows))
+	}
+
+	row := conn.rows[0]
+	if row[1] != testDeviceID || row[2] != "web-1" || row[3] != "http_requests_total" || row[4] != "counter" {
+		t.Errorf("unexpected metric %v", row[1:5])
+	}
+	if labels := row[6].(map[string]string); labels["code"] != "200" {
+		t.Errorf("expected the code label, got %v", labels)
+	}
+	if value := row[7].(float64); value != 42 {
+		t.Errorf("expected value 42, got %v", value)
+	}
+}
+
+func TestRemoteWriteKeyWithoutTLS(t *testing.T) {
+	conn := &fakeConn{}
+	rcv := NewRemoteWrite(newTestServer(conn), APIKeys{"key": testDeviceID}, 1)
+
+	payload, err := proto.Marshal(&prometheusv1.WriteRequest{})
+	if err != nil {
+		t.Fatalf("failed to marshal write request: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "http://ingest/api/v1/write", bytes.NewReader(s2.EncodeSnappy(nil, payload)))
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Authorization", "Bearer key")
+
+	rec := httptest.NewRecorder()
+	rcv.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a key sent without TLS, got %d", rec.Code)
+	}
+}
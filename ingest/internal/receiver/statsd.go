@@ -0,0 +1,433 @@
+package receiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/netip"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/microwatcher/ingest/internal"
+	v2 "github.com/microwatcher/shared/pkg/gen/microwatcher/v2"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const DefaultStatsDFlushInterval = 10 * time.Second
+
+// DefaultStatsDIdentifier is stored for metrics without a host tag.
+const DefaultStatsDIdentifier = "statsd"
+
+// maxStatsDSeries bounds the aggregation state, samples of new series past
+// it are dropped.
+const maxStatsDSeries = 10000
+
+// statsDIdleFlushes is how many flushes a series goes without samples
+// before it's forgotten, so series of short lived tags free their slot.
+const statsDIdleFlushes = 30
+
+// statsDTimerBounds are the histogram buckets of timers, in milliseconds.
+var statsDTimerBounds = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+type statsDSample struct {
+	name   string
+	kind   string
+	value  float64
+	member string
+	// gauges with an explicit sign move the current value
+	relative bool
+	rate     float64
+	labels   map[string]string
+}
+
+// parseStatsDLine parses name:value|type[|@rate][|#tag:value,tag], the tags
+// are DogStatsD's extension.
+func parseStatsDLine(line string) (statsDSample, error) {
+	name, rest, ok := strings.Cut(line, ":")
+	if !ok || name == "" {
+		return statsDSample{}, fmt.Errorf("invalid statsd line %q", line)
+	}
+
+	parts := strings.Split(rest, "|")
+	if len(parts) < 2 {
+		return statsDSample{}, fmt.Errorf("statsd line %q has no type", line)
+	}
+
+	sample := statsDSample{name: name, kind: parts[1], rate: 1, labels: map[string]string{}}
+	for _, part := range parts[2:] {
+		switch {
+		case strings.HasPrefix(part, "@"):
+			rate, err := strconv.ParseFloat(part[1:], 64)
+			if err != nil || rate <= 0 || rate > 1 {
+				return statsDSample{}, fmt.Errorf("statsd line %q has an invalid sample rate", line)
+			}
+			sample.rate = rate
+		case strings.HasPrefix(part, "#"):
+			for _, tag := range strings.Split(part[1:], ",") {
+				if tag == "" {
+					continue
+				}
+				key, val, _ := strings.Cut(tag, ":")
+				sample.labels[key] = val
+			}
+		}
+	}
+
+	switch sample.kind {
+	case "s":
+		sample.member = parts[0]
+		return sample, nil
+	case "c", "g", "ms", "h", "d":
+	default:
+		return statsDSample{}, fmt.Errorf("statsd line %q has unknown type %q", line, sample.kind)
+	}
+
+	value, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return statsDSample{}, fmt.Errorf("statsd line %q has an invalid value", line)
+	}
+	sample.value = value
+	sample.relative = sample.kind == "g" && (strings.HasPrefix(parts[0], "+") || strings.HasPrefix(parts[0], "-"))
+
+	return sample, nil
+}
+
+// statsDSeries is the aggregation of one metric and tag set. Counters and
+// timers accumulate from the start of the listener so they are stored like
+// Prometheus counters and histograms, a restart of ingest is a reset.
+type statsDSeries struct {
+	name    string
+	kind    string
+	labels  map[string]string
+	updated bool
+	// flushes since the last sample
+	idle int
+
+	value   float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+	members map[string]struct{}
+}
+
+func statsDKey(sample statsDSample) string {
+	names := make([]string, 0, len(sample.labels))
+	for name := range sample.labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	key.WriteString(sample.kind)
+	key.WriteByte('|')
+	key.WriteString(sample.name)
+	for _, name := range names {
+		key.WriteByte('|')
+		key.WriteString(name)
+		key.WriteByte('=')
+		key.WriteString(sample.labels[name])
+	}
+
+	return key.String()
+}
+
+// DefaultStatsDAllowedNetworks only lets in local clients, StatsD has no
+// authentication so wider networks have to be allowed explicitly.
+var DefaultStatsDAllowedNetworks = []netip.Prefix{
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("::1/128"),
+}
+
+// ParseAllowedNetworks parses a comma separated list of CIDRs, single
+// addresses are allowed on their own.
+func ParseAllowedNetworks(val string) ([]netip.Prefix, error) {
+	var networks []netip.Prefix
+	for _, cidr := range strings.Split(val, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		if !strings.Contains(cidr, "/") {
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q", cidr)
+			}
+			networks = append(networks, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		network, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr %q", cidr)
+		}
+		networks = append(networks, network.Masked())
+	}
+
+	return networks, nil
+}
+
+// StatsD listens for StatsD over UDP and flushes the aggregates as v2
+// metrics every FlushInterval. StatsD has no authentication, packets from
+// outside AllowedNetworks are dropped and everything else is stored under
+// its device.
+type StatsD struct {
+	Server          *internal.Server
+	DeviceID        string
+	FlushInterval   time.Duration
+	AllowedNetworks []netip.Prefix
+
+	mu      sync.Mutex
+	series  map[string]*statsDSeries
+	dropped int
+	// packets from outside AllowedNetworks since the last flush
+	denied atomic.Int64
+}
+
+func NewStatsD(server *internal.Server, deviceID string, flushInterval time.Duration, allowedNetworks []netip.Prefix) *StatsD {
+	if flushInterval <= 0 {
+		flushInterval = DefaultStatsDFlushInterval
+	}
+	if len(allowedNetworks) == 0 {
+		allowedNetworks = DefaultStatsDAllowedNetworks
+	}
+
+	return &StatsD{
+		Server:          server,
+		DeviceID:        deviceID,
+		FlushInterval:   flushInterval,
+		AllowedNetworks: allowedNetworks,
+		series:          map[string]*statsDSeries{},
+	}
+}
+
+// allowed reports whether packets from addr are accepted.
+func (rcv *StatsD) allowed(addr net.Addr) bool {
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return false
+	}
+
+	ip := udpAddr.AddrPort().Addr().Unmap()
+	return slices.ContainsFunc(rcv.AllowedNetworks, func(network netip.Prefix) bool {
+		return network.Contains(ip)
+	})
+}
+
+// ListenAndServe receives packets on addr until ctx is done, then flushes
+// what is left.
+func (rcv *StatsD) ListenAndServe(ctx context.Context, addr string) error {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return errors.Join(errors.New("failed to listen"), err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	go func() {
+		ticker := time.NewTicker(rcv.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// ctx is done, the last flush still needs to reach clickhouse
+				rcv.flush(context.WithoutCancel(ctx))
+				return
+			case <-ticker.C:
+				rcv.flush(ctx)
+			}
+		}
+	}()
+
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Join(errors.New("failed to read packet"), err)
+		}
+
+		// counted and logged on flush, a flood of them would flood the logs
+		if !rcv.allowed(addr) {
+			rcv.denied.Add(1)
+			continue
+		}
+
+		rcv.handlePacket(buf[:n])
+	}
+}
+
+func (rcv *StatsD) handlePacket(packet []byte) {
+	for _, line := range strings.Split(string(packet), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		sample, err := parseStatsDLine(line)
+		if err != nil {
+			rcv.Server.Logger.Warn("dropping statsd line",
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+
+		rcv.add(sample)
+	}
+}
+
+func (rcv *StatsD) add(sample statsDSample) {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	key := statsDKey(sample)
+	series, ok := rcv.series[key]
+	if !ok {
+		if len(rcv.series) >= maxStatsDSeries {
+			rcv.dropped++
+			return
+		}
+
+		series = &statsDSeries{name: sample.name, kind: sample.kind, labels: sample.labels}
+		rcv.series[key] = series
+	}
+	series.updated = true
+
+	switch sample.kind {
+	case "c":
+		series.value += sample.value / sample.rate
+	case "g":
+		if sample.relative {
+			series.value += sample.value
+		} else {
+			series.value = sample.value
+		}
+	case "s":
+		if series.members == nil {
+			series.members = map[string]struct{}{}
+		}
+		series.members[sample.member] = struct{}{}
+	default:
+		if series.buckets == nil {
+			series.buckets = make([]uint64, len(statsDTimerBounds)+1)
+		}
+
+		// a sampled timer stands for 1/rate timings
+		weight := uint64(math.Round(1 / sample.rate))
+		bucket, _ := slices.BinarySearch(statsDTimerBounds, sample.value)
+		series.buckets[bucket] += weight
+		series.sum += sample.value * float64(weight)
+		series.count += weight
+	}
+}
+
+// collect turns the series updated since the previous flush into metrics,
+// grouped by the host tag as identifier. Series idle for statsDIdleFlushes
+// are evicted, counters and timers coming back start over from zero like
+// after a restart of ingest.
+func (rcv *StatsD) collect(now time.Time) (map[string][]*v2.Metric, int) {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	timestamp := timestamppb.New(now)
+	grouped := map[string][]*v2.Metric{}
+	for key, series := range rcv.series {
+		if !series.updated {
+			series.idle++
+			if series.idle >= statsDIdleFlushes {
+				delete(rcv.series, key)
+			}
+			continue
+		}
+		series.updated = false
+		series.idle = 0
+
+		metric := &v2.Metric{
+			Timestamp: timestamp,
+			Name:      series.name,
+			Labels:    series.labels,
+		}
+
+		switch series.kind {
+		case "c":
+			metric.Type = v2.MetricType_METRIC_TYPE_COUNTER
+			metric.Data = &v2.Metric_Value{Value: series.value}
+		case "g":
+			metric.Type = v2.MetricType_METRIC_TYPE_GAUGE
+			metric.Data = &v2.Metric_Value{Value: series.value}
+		case "s":
+			// unique members seen during the interval
+			metric.Type = v2.MetricType_METRIC_TYPE_GAUGE
+			metric.Data = &v2.Metric_Value{Value: float64(len(series.members))}
+			series.members = nil
+		default:
+			buckets := make([]*v2.HistogramBucket, len(series.buckets))
+			var cumulative uint64
+			for idx, count := range series.buckets {
+				cumulative += count
+
+				bound := math.Inf(1)
+				if idx < len(statsDTimerBounds) {
+					bound = statsDTimerBounds[idx]
+				}
+				buckets[idx] = &v2.HistogramBucket{UpperBound: bound, Count: cumulative}
+			}
+
+			metric.Type = v2.MetricType_METRIC_TYPE_HISTOGRAM
+			metric.Unit = "ms"
+			metric.Data = &v2.Metric_Histogram{Histogram: &v2.Histogram{
+				Buckets: buckets,
+				Sum:     series.sum,
+				Count:   series.count,
+			}}
+		}
+
+		identifier := series.labels["host"]
+		if identifier == "" {
+			identifier = DefaultStatsDIdentifier
+		}
+		grouped[identifier] = append(grouped[identifier], metric)
+	}
+
+	dropped := rcv.dropped
+	rcv.dropped = 0
+
+	return grouped, dropped
+}
+
+func (rcv *StatsD) flush(ctx context.Context) {
+	grouped, dropped := rcv.collect(time.Now())
+	if dropped > 0 {
+		rcv.Server.Logger.Warn("dropped statsd samples of new series, too many series",
+			slog.Int("dropped", dropped),
+			slog.Int("max series", maxStatsDSeries),
+		)
+	}
+	if denied := rcv.denied.Swap(0); denied > 0 {
+		rcv.Server.Logger.Warn("dropped statsd packets from sources outside the allowed networks",
+			slog.Int64("dropped", denied),
+		)
+	}
+
+	for identifier, metrics := range grouped {
+		if err := rcv.Server.Clickhouse.IngestV2Metrics(ctx, rcv.DeviceID, identifier, metrics); err != nil {
+			rcv.Server.Logger.Error("failed to ingest statsd metrics",
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
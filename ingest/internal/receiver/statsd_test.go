@@ -0,0 +1,109 @@
+package receiver
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseStatsDLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want statsDSample
+	}{
+		{
+			line: "requests:1|c",
+			want: statsDSample{name: "requests", kind: "c", value: 1, rate: 1, labels: map[string]string{}},
+		},
+		{
+			line: "requests:3|c|@0.1",
+			want: statsDSample{name: "requests", kind: "c", value: 3, rate: 0.1, labels: map[string]string{}},
+		},
+		{
+			line: "queue.depth:42|g",
+			want: statsDSample{name: "queue.depth", kind: "g", value: 42, rate: 1, labels: map[string]string{}},
+		},
+		{
+			line: "queue.depth:+5|g",
+			want: statsDSample{name: "queue.depth", kind: "g", value: 5, relative: true, rate: 1, labels: map[string]string{}},
+		},
+		{
+			line: "queue.depth:-5|g",
+			want: statsDSample{name: "queue.depth", kind: "g", value: -5, relative: true, rate: 1, labels: map[string]string{}},
+		},
+		{
+			line: "users:alice|s",
+			want: statsDSample{name: "users", kind: "s", member: "alice", rate: 1, labels: map[string]string{}},
+		},
+		{
+			line: "request.duration:12.5|ms|@0.5|#host:web-1,route:/users,canary",
+			want: statsDSample{name: "request.duration", kind: "ms", value: 12.5, rate: 0.5, labels: map[string]string{
+				"host":   "web-1",
+				"route":  "/users",
+				"canary": "",
+			}},
+		},
+		{
+			line: "payload.size:512|h|#env:prod",
+			want: statsDSample{name: "payload.size", kind: "h", value: 512, rate: 1, labels: map[string]string{"env": "prod"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.line, func(t *testing.T) {
+			got, err := parseStatsDLine(test.line)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseStatsDLineErrors(t *testing.T) {
+	for _, line := range []string{
+		"requests",
+		":1|c",
+		"requests:1",
+		"requests:1|x",
+		"requests:abc|c",
+		"requests:NaN|g",
+		"requests:1|c|@0",
+		"requests:1|c|@2",
+		"requests:1|c|@rate",
+	} {
+		t.Run(line, func(t *testing.T) {
+			if _, err := parseStatsDLine(line); err == nil {
+				t.Errorf("expected %q to fail", line)
+			}
+		})
+	}
+}
+
+func TestStatsDEvictsIdleSeries(t *testing.T) {
+	rcv := NewStatsD(nil, testDeviceID, 0, nil)
+
+	sample, err := parseStatsDLine("request.duration:12|ms|#route:/users/42")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	rcv.add(sample)
+
+	if grouped, _ := rcv.collect(time.Now()); len(grouped[DefaultStatsDIdentifier]) != 1 {
+		t.Fatalf("expected the updated series to be flushed, got %v", grouped)
+	}
+
+	for range statsDIdleFlushes - 1 {
+		rcv.collect(time.Now())
+	}
+	if len(rcv.series) != 1 {
+		t.Fatalf("expected the series to be kept until it was idle for %d flushes", statsDIdleFlushes)
+	}
+
+	rcv.collect(time.Now())
+	if len(rcv.series) != 0 {
+		t.Errorf("expected the idle series to be evicted, %d left", len(rcv.series))
+	}
+}
@@ -16,7 +16,9 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -24,6 +26,8 @@ type Server struct {
 	Logger     *slog.Logger
 	Clickhouse *clickhouse.ClickhouseSource
 	v1.UnimplementedTelemetryServiceServer
+
+	units unitStates
 }
 
 func extractHeader(md metadata.MD, key string) string {
@@ -126,6 +130,163 @@ func (svc *Server) ValidateSignature(ctx context.Context, signature string, devi
 	return nil
 }
 
+// DeviceFromCertificate returns the device ID carried in the common name of a
+// verified client certificate, if the connection presented one.
+func (svc *Server) DeviceFromCertificate(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return "", false
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.PeerCertificates) == 0 {
+		return "", false
+	}
+
+	deviceID := tlsInfo.State.PeerCertificates[0].Subject.CommonName
+	if !uuidv7.IsValidString(deviceID) {
+		return "", false
+	}
+
+	return deviceID, true
+}
+
+// Authenticate resolves the device behind a request, either from a verified
+// client certificate or from the HMAC signature headers.
+func (svc *Server) Authenticate(ctx context.Context, msg proto.Message) (string, error) {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "Server.Authenticate")
+	defer span.End()
+
+	if deviceID, ok := svc.DeviceFromCertificate(ctx); ok {
+		span.SetAttributes(
+			attribute.String("deviceID", deviceID),
+			attribute.String("method", "certificate"),
+		)
+
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if clientID := extractHeader(md, "x-client-id"); clientID != "" && clientID != deviceID {
+				svc.Logger.Error("client id does not match certificate",
+					slog.String("clientID", clientID),
+					slog.String("deviceID", deviceID),
+				)
+
+				span.RecordError(fmt.Errorf("client id does not match certificate"))
+				span.SetStatus(codes.Error, "client id does not match certificate")
+				return "", fmt.Errorf("client id does not match certificate")
+			}
+		}
+
+		if _, err := svc.Clickhouse.FindDeviceByID(spanCtx, deviceID); err != nil {
+			svc.Logger.Error("failed to find client",
+				slog.String("error", err.Error()),
+			)
+
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to find client")
+			return "", errors.Join(errors.New("failed to find client"), err)
+		}
+
+		span.SetStatus(codes.Ok, "authenticated with certificate")
+		return deviceID, nil
+	}
+
+	signature, deviceID, err := svc.ValidateMetadata(spanCtx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+
+	if err := svc.ValidateSignature(spanCtx, signature, deviceID, msg); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", errors.Join(errors.New("failed to validate signature"), err)
+	}
+
+	span.SetAttributes(
+		attribute.String("deviceID", deviceID),
+		attribute.String("method", "signature"),
+	)
+	span.SetStatus(codes.Ok, "authenticated with signature")
+
+	return deviceID, nil
+}
+
+// overTLS reports whether the call came in over a TLS connection.
+func overTLS(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return false
+	}
+
+	_, ok = p.AuthInfo.(credentials.TLSInfo)
+	return ok
+}
+
+// AuthenticateReceiver authenticates requests from third party clients, e.g.
+// OTel SDKs, which can't sign payloads but can send static headers. They may
+// send the device secret in x-client-secret instead of a signature, which is
+// rejected unless the connection is TLS.
+func (svc *Server) AuthenticateReceiver(ctx context.Context, msg proto.Message) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if _, hasCert := svc.DeviceFromCertificate(ctx); hasCert || !ok || extractHeader(md, "x-client-secret") == "" {
+		return svc.Authenticate(ctx, msg)
+	}
+
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "Server.AuthenticateReceiver")
+	defer span.End()
+
+	if !overTLS(ctx) {
+		svc.Logger.Error("client secret sent without TLS",
+			slog.String("clientID", extractHeader(md, "x-client-id")),
+		)
+
+		span.RecordError(fmt.Errorf("client secret is only accepted over TLS"))
+		span.SetStatus(codes.Error, "client secret sent without TLS")
+		return "", fmt.Errorf("client secret is only accepted over TLS")
+	}
+
+	deviceID := extractHeader(md, "x-client-id")
+	if !uuidv7.IsValidString(deviceID) {
+		svc.Logger.Error("invalid client id",
+			slog.String("clientID", deviceID),
+		)
+
+		span.RecordError(fmt.Errorf("invalid client id"))
+		span.SetStatus(codes.Error, "invalid client id")
+		return "", fmt.Errorf("invalid client id")
+	}
+
+	deviceInfo, err := svc.Clickhouse.FindDeviceByID(spanCtx, deviceID)
+	if err != nil {
+		svc.Logger.Error("failed to find client",
+			slog.String("error", err.Error()),
+		)
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to find client")
+		return "", errors.Join(errors.New("failed to find client"), err)
+	}
+
+	if !hmac.Equal([]byte(extractHeader(md, "x-client-secret")), []byte(deviceInfo.Secret)) {
+		svc.Logger.Error("invalid client secret",
+			slog.String("clientID", deviceID),
+		)
+
+		span.RecordError(fmt.Errorf("invalid client secret"))
+		span.SetStatus(codes.Error, "invalid client secret")
+		return "", fmt.Errorf("invalid client secret")
+	}
+
+	span.SetAttributes(
+		attribute.String("deviceID", deviceID),
+		attribute.String("method", "secret"),
+	)
+	span.SetStatus(codes.Ok, "authenticated with secret")
+
+	return deviceID, nil
+}
+
 func (svc *Server) Ping(ctx context.Context, req *v1.PingRequest) (*v1.PingResponse, error) {
 	spanCtx, span := otlp.IngestTracer.Start(ctx, "Server.Ping",
 		trace.WithAttributes(attribute.String("method", "Ping")),
@@ -133,21 +294,14 @@ func (svc *Server) Ping(ctx context.Context, req *v1.PingRequest) (*v1.PingRespo
 	)
 	defer span.End()
 
-	signature, deviceID, err := svc.ValidateMetadata(ctx)
+	deviceID, err := svc.Authenticate(spanCtx, req)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
 
-	if err := svc.ValidateSignature(spanCtx, signature, deviceID, req); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return nil, errors.Join(errors.New("failed to validate signature"), err)
-	}
-
 	svc.Logger.Info("ping received",
-		slog.Any("signature", signature),
 		slog.Any("deviceID", deviceID),
 	)
 
@@ -161,17 +315,30 @@ func (svc *Server) HealthCheck(ctx context.Context, req *v1.HealthCheckRequest)
 	)
 	defer span.End()
 
-	signature, deviceID, err := svc.ValidateMetadata(ctx)
+	deviceID, err := svc.Authenticate(spanCtx, req)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
 
-	if err := svc.ValidateSignature(spanCtx, signature, deviceID, req); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return nil, errors.Join(errors.New("failed to validate signature"), err)
+	if req.Inventory != nil {
+		// unlike the health check itself the agent has to know, it only
+		// sends the inventory again when it changes. Stored first, so a
+		// failing health check can't get it marked as sent.
+		if err := svc.Clickhouse.IngestV1Inventory(spanCtx, deviceID, req); err != nil {
+			svc.Logger.Error("failed to ingest inventory",
+				slog.String("error", err.Error()),
+			)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to ingest inventory")
+			return nil, errors.New("failed to ingest inventory")
+		}
+
+		svc.Logger.Info("inventory ingested",
+			slog.String("deviceID", deviceID),
+			slog.String("agentVersion", req.Inventory.AgentVersion),
+		)
 	}
 
 	// TODO: maybe retry or send to a "dead" queue to retry later
@@ -194,17 +361,22 @@ func (svc *Server) SendTelemetry(ctx context.Context, req *v1.SendTelemetryReque
 	)
 	defer span.End()
 
-	signature, deviceID, err := svc.ValidateMetadata(ctx)
+	deviceID, err := svc.Authenticate(spanCtx, req)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
 
-	if err := svc.ValidateSignature(spanCtx, signature, deviceID, req); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return nil, errors.Join(errors.New("failed to validate signature"), err)
+	for _, telemetry := range req.Telemetries {
+		for _, collectorErr := range telemetry.CollectorErrors {
+			svc.Logger.Warn("agent collector failed",
+				slog.String("deviceID", deviceID),
+				slog.String("identifier", telemetry.Identifier),
+				slog.String("collector", collectorErr.Collector),
+				slog.String("error", collectorErr.Error),
+			)
+		}
 	}
 
 	// TODO: maybe retry or send to a "dead" queue to retry later
@@ -226,6 +398,84 @@ func (svc *Server) SendTelemetry(ctx context.Context, req *v1.SendTelemetryReque
 		return &v1.SendTelemetryResponse{Success: false}, nil
 	}
 
+	if err := svc.Clickhouse.IngestV1CPUCoreTelemetries(spanCtx, deviceID, req.Telemetries); err != nil {
+		svc.Logger.Error("failed to ingest cpu core telemetries",
+			slog.String("error", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest telemetries")
+		return &v1.SendTelemetryResponse{Success: false}, nil
+	}
+
+	if err := svc.Clickhouse.IngestV1SystemTelemetries(spanCtx, deviceID, req.Telemetries); err != nil {
+		svc.Logger.Error("failed to ingest system telemetries",
+			slog.String("error", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest telemetries")
+		return &v1.SendTelemetryResponse{Success: false}, nil
+	}
+
+	if err := svc.Clickhouse.IngestV1ProcessTelemetries(spanCtx, deviceID, req.Telemetries); err != nil {
+		svc.Logger.Error("failed to ingest process telemetries",
+			slog.String("error", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest telemetries")
+		return &v1.SendTelemetryResponse{Success: false}, nil
+	}
+
+	if err := svc.Clickhouse.IngestV1SensorTelemetries(spanCtx, deviceID, req.Telemetries); err != nil {
+		svc.Logger.Error("failed to ingest sensor telemetries",
+			slog.String("error", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest telemetries")
+		return &v1.SendTelemetryResponse{Success: false}, nil
+	}
+
+	if err := svc.Clickhouse.IngestV1CgroupTelemetries(spanCtx, deviceID, req.Telemetries); err != nil {
+		svc.Logger.Error("failed to ingest cgroup telemetries",
+			slog.String("error", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest telemetries")
+		return &v1.SendTelemetryResponse{Success: false}, nil
+	}
+
+	if err := svc.Clickhouse.IngestV1CertificateTelemetries(spanCtx, deviceID, req.Telemetries); err != nil {
+		svc.Logger.Error("failed to ingest certificate telemetries",
+			slog.String("error", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest telemetries")
+		return &v1.SendTelemetryResponse{Success: false}, nil
+	}
+
+	unitEvents, err := svc.unitTransitions(spanCtx, deviceID, req.Telemetries)
+	if err != nil {
+		svc.Logger.Error("failed to detect unit transitions",
+			slog.String("error", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest telemetries")
+		return &v1.SendTelemetryResponse{Success: false}, nil
+	}
+
+	if len(unitEvents) > 0 {
+		if err := svc.Clickhouse.IngestV1UnitEvents(spanCtx, deviceID, unitEvents); err != nil {
+			// the retried batch has to detect the same transitions again
+			svc.forgetUnits(deviceID)
+
+			svc.Logger.Error("failed to ingest unit events",
+				slog.String("error", err.Error()),
+			)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to ingest telemetries")
+			return &v1.SendTelemetryResponse{Success: false}, nil
+		}
+	}
+
 	if err := svc.Clickhouse.IngestV1DisksTelemetries(spanCtx, deviceID, req.Telemetries); err != nil {
 		svc.Logger.Error("failed to ingest disks telemetries",
 			slog.String("error", err.Error()),
@@ -235,6 +485,15 @@ func (svc *Server) SendTelemetry(ctx context.Context, req *v1.SendTelemetryReque
 		return &v1.SendTelemetryResponse{Success: false}, nil
 	}
 
+	if err := svc.Clickhouse.IngestV1DiskIOTelemetries(spanCtx, deviceID, req.Telemetries); err != nil {
+		svc.Logger.Error("failed to ingest disk io telemetries",
+			slog.String("error", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest telemetries")
+		return &v1.SendTelemetryResponse{Success: false}, nil
+	}
+
 	if err := svc.Clickhouse.IngestV1NetworksTelemetries(spanCtx, deviceID, req.Telemetries); err != nil {
 		svc.Logger.Error("failed to ingest networks telemetries",
 			slog.String("error", err.Error()),
@@ -251,3 +510,99 @@ func (svc *Server) SendTelemetry(ctx context.Context, req *v1.SendTelemetryReque
 
 	return &v1.SendTelemetryResponse{Success: true}, nil
 }
+
+func (svc *Server) SendMetrics(ctx context.Context, req *v1.SendMetricsRequest) (*v1.SendMetricsResponse, error) {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "Server.SendMetrics",
+		trace.WithAttributes(attribute.String("method", "SendMetrics")),
+		trace.WithAttributes(attribute.Int("batch size", len(req.Metrics))),
+	)
+	defer span.End()
+
+	deviceID, err := svc.Authenticate(spanCtx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// TODO: maybe retry or send to a "dead" queue to retry later
+	if err := svc.Clickhouse.IngestV1CustomMetrics(spanCtx, deviceID, req); err != nil {
+		svc.Logger.Error("failed to ingest custom metrics",
+			slog.String("error", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest metrics")
+		return &v1.SendMetricsResponse{Success: false}, nil
+	}
+
+	svc.Logger.Info("metrics ingested",
+		slog.Int("size", len(req.Metrics)),
+	)
+	span.SetStatus(codes.Ok, "ingested")
+
+	return &v1.SendMetricsResponse{Success: true}, nil
+}
+
+func (svc *Server) SendLogs(ctx context.Context, req *v1.SendLogsRequest) (*v1.SendLogsResponse, error) {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "Server.SendLogs",
+		trace.WithAttributes(attribute.String("method", "SendLogs")),
+		trace.WithAttributes(attribute.Int("batch size", len(req.Entries))),
+	)
+	defer span.End()
+
+	deviceID, err := svc.Authenticate(spanCtx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// TODO: maybe retry or send to a "dead" queue to retry later
+	if err := svc.Clickhouse.IngestV1Logs(spanCtx, deviceID, req); err != nil {
+		svc.Logger.Error("failed to ingest logs",
+			slog.String("error", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest logs")
+		return &v1.SendLogsResponse{Success: false}, nil
+	}
+
+	svc.Logger.Info("logs ingested",
+		slog.Int("size", len(req.Entries)),
+	)
+	span.SetStatus(codes.Ok, "ingested")
+
+	return &v1.SendLogsResponse{Success: true}, nil
+}
+
+func (svc *Server) SendProbeResults(ctx context.Context, req *v1.SendProbeResultsRequest) (*v1.SendProbeResultsResponse, error) {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "Server.SendProbeResults",
+		trace.WithAttributes(attribute.String("method", "SendProbeResults")),
+		trace.WithAttributes(attribute.Int("batch size", len(req.Results))),
+	)
+	defer span.End()
+
+	deviceID, err := svc.Authenticate(spanCtx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// TODO: maybe retry or send to a "dead" queue to retry later
+	if err := svc.Clickhouse.IngestV1ProbeResults(spanCtx, deviceID, req); err != nil {
+		svc.Logger.Error("failed to ingest probe results",
+			slog.String("error", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest probe results")
+		return &v1.SendProbeResultsResponse{Success: false}, nil
+	}
+
+	svc.Logger.Info("probe results ingested",
+		slog.Int("size", len(req.Results)),
+	)
+	span.SetStatus(codes.Ok, "ingested")
+
+	return &v1.SendProbeResultsResponse{Success: true}, nil
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/microwatcher/shared/pkg/clickhouse"
+	v2 "github.com/microwatcher/shared/pkg/gen/microwatcher/v2"
+	"github.com/microwatcher/shared/pkg/otlp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MetricsServer serves microwatcher.v2 next to the v1 Server, devices
+// authenticate the same way on both.
+type MetricsServer struct {
+	Server *Server
+	v2.UnimplementedMetricsServiceServer
+}
+
+func (svc *MetricsServer) SendMetrics(ctx context.Context, req *v2.SendMetricsRequest) (*v2.SendMetricsResponse, error) {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "MetricsServer.SendMetrics",
+		trace.WithAttributes(attribute.String("method", "SendMetrics")),
+		trace.WithAttributes(attribute.Int("batch size", len(req.Metrics))),
+	)
+	defer span.End()
+
+	deviceID, err := svc.Server.Authenticate(spanCtx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// TODO: maybe retry or send to a "dead" queue to retry later
+	if err := svc.Server.Clickhouse.IngestV2Metrics(spanCtx, deviceID, req.Identifier, req.Metrics); err != nil {
+		svc.Server.Logger.Error("failed to ingest metrics",
+			slog.String("error", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to ingest metrics")
+		if errors.Is(err, clickhouse.ErrInvalidMetric) {
+			return nil, status.Error(grpccodes.InvalidArgument, err.Error())
+		}
+		return &v2.SendMetricsResponse{Success: false}, nil
+	}
+
+	svc.Server.Logger.Info("metrics ingested",
+		slog.Int("size", len(req.Metrics)),
+	)
+	span.SetStatus(codes.Ok, "ingested")
+
+	return &v2.SendMetricsResponse{Success: true}, nil
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/microwatcher/shared/pkg/clickhouse"
+	v1 "github.com/microwatcher/shared/pkg/gen/microwatcher/v1"
+)
+
+// unitStates remembers the last reported state of every systemd unit, so only
+// transitions get stored. Devices are seeded from unit_events the first time
+// they report after a restart.
+type unitStates struct {
+	mu      sync.Mutex
+	devices map[string]map[string]*clickhouse.ClickhouseUnitEvent
+}
+
+// unitTransitions returns the units whose active or sub state changed, or
+// that restarted, since they were last reported.
+func (svc *Server) unitTransitions(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) ([]*clickhouse.ClickhouseUnitEvent, error) {
+	svc.units.mu.Lock()
+	defer svc.units.mu.Unlock()
+
+	if svc.units.devices == nil {
+		svc.units.devices = make(map[string]map[string]*clickhouse.ClickhouseUnitEvent)
+	}
+
+	last, ok := svc.units.devices[deviceID]
+	if !ok {
+		var err error
+		last, err = svc.Clickhouse.LastUnitEvents(ctx, deviceID)
+		if err != nil {
+			return nil, errors.Join(errors.New("failed to load last unit events"), err)
+		}
+		svc.units.devices[deviceID] = last
+	}
+
+	var events []*clickhouse.ClickhouseUnitEvent
+	for _, telemetry := range telemetries {
+		for _, unit := range telemetry.Units {
+			event := &clickhouse.ClickhouseUnitEvent{
+				Timestamp:   telemetry.Timestamp.AsTime(),
+				Identifier:  telemetry.Identifier,
+				Unit:        unit.Name,
+				LoadState:   unit.LoadState,
+				ActiveState: unit.ActiveState,
+				SubState:    unit.SubState,
+				Restarts:    unit.Restarts,
+				MainPID:     unit.MainPid,
+			}
+
+			previous, ok := last[unit.Name]
+			if ok {
+				event.PreviousActiveState = previous.ActiveState
+				event.PreviousSubState = previous.SubState
+			}
+			last[unit.Name] = event
+
+			// the restart counter resets with the host, that alone isn't a
+			// transition
+			if ok && previous.ActiveState == unit.ActiveState &&
+				previous.SubState == unit.SubState &&
+				unit.Restarts <= previous.Restarts {
+				continue
+			}
+
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// forgetUnits drops the remembered states of a device, they're reloaded from
+// unit_events on its next report.
+func (svc *Server) forgetUnits(deviceID string) {
+	svc.units.mu.Lock()
+	defer svc.units.mu.Unlock()
+
+	delete(svc.units.devices, deviceID)
+}
@@ -2,22 +2,35 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"log/slog"
 	"net"
+	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	_ "github.com/joho/godotenv/autoload"
 	"github.com/microwatcher/ingest/internal"
+	"github.com/microwatcher/ingest/internal/certs"
 	"github.com/microwatcher/ingest/internal/otlp"
+	"github.com/microwatcher/ingest/internal/receiver"
 	"github.com/microwatcher/shared/pkg/clickhouse"
 	v1 "github.com/microwatcher/shared/pkg/gen/microwatcher/v1"
+	v2 "github.com/microwatcher/shared/pkg/gen/microwatcher/v2"
 	"github.com/microwatcher/shared/pkg/logger"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 const Port = "50051"
 
+// HTTPAddr serves the push receivers, 4318 is the OTLP/HTTP default port
+const HTTPAddr = ":4318"
+
 func main() {
 	logger := logger.NewDefaultLogger()
 
@@ -41,13 +54,128 @@ func main() {
 		os.Exit(1)
 	}
 
-	s := grpc.NewServer(
+	serverOpts := []grpc.ServerOption{
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
-	)
-	v1.RegisterTelemetryServiceServer(s, &internal.Server{
+	}
+
+	var httpTLSConfig *tls.Config
+	certFile, keyFile := os.Getenv("MW_TLS_CERT_FILE"), os.Getenv("MW_TLS_KEY_FILE")
+	if certFile != "" || keyFile != "" {
+		reloader, err := certs.NewReloader(certFile, keyFile, os.Getenv("MW_TLS_CLIENT_CA_FILE"), logger)
+		if err != nil {
+			logger.Error("failed to load certificates",
+				slog.String("error", err.Error()),
+			)
+			os.Exit(1)
+		}
+
+		httpTLSConfig = reloader.HTTPConfig()
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(reloader.Config())))
+		logger.Info("serving with TLS",
+			slog.String("cert", certFile),
+			slog.Bool("client certificates", reloader.ClientCAFile != ""),
+		)
+	}
+
+	server := &internal.Server{
 		Logger:     logger,
 		Clickhouse: localSource,
-	})
+	}
+
+	// v1 stays served while agents migrate to v2
+	s := grpc.NewServer(serverOpts...)
+	v1.RegisterTelemetryServiceServer(s, server)
+	v2.RegisterMetricsServiceServer(s, &internal.MetricsServer{Server: server})
+
+	apiKeys, err := receiver.ParseAPIKeys(os.Getenv("MW_API_KEYS"))
+	if err != nil {
+		logger.Error("failed to parse api keys",
+			slog.String("error", err.Error()),
+		)
+		os.Exit(1)
+	}
+
+	remoteWriteInflight := receiver.DefaultRemoteWriteInflight
+	if val := os.Getenv("MW_REMOTE_WRITE_INFLIGHT"); val != "" {
+		remoteWriteInflight, err = strconv.Atoi(val)
+		if err != nil || remoteWriteInflight <= 0 {
+			logger.Error("invalid remote write inflight limit",
+				slog.String("value", val),
+			)
+			os.Exit(1)
+		}
+	}
+
+	otlpReceiver := &receiver.OTLP{Server: server, APIKeys: apiKeys}
+	colmetricspb.RegisterMetricsServiceServer(s, otlpReceiver)
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/metrics", otlpReceiver)
+	mux.Handle("/api/v1/write", receiver.NewRemoteWrite(server, apiKeys, remoteWriteInflight))
+
+	httpAddr := os.Getenv("MW_HTTP_ADDR")
+	if httpAddr == "" {
+		httpAddr = HTTPAddr
+	}
+
+	go serveHTTP(logger, "http", httpAddr, mux, httpTLSConfig)
+
+	// optional listeners for devices that can only push these formats
+	if influxAddr := os.Getenv("MW_INFLUX_ADDR"); influxAddr != "" {
+		influxKeys, err := receiver.ParseAPIKeys(os.Getenv("MW_INFLUX_API_KEYS"))
+		if err != nil {
+			logger.Error("failed to parse influx api keys",
+				slog.String("error", err.Error()),
+			)
+			os.Exit(1)
+		}
+
+		influx := &receiver.Influx{Server: server, APIKeys: influxKeys}
+		go serveHTTP(logger, "influx", influxAddr, influx.Handler(), httpTLSConfig)
+	}
+
+	if statsdAddr := os.Getenv("MW_STATSD_ADDR"); statsdAddr != "" {
+		deviceID := os.Getenv("MW_STATSD_DEVICE_ID")
+		if _, err := localSource.FindDeviceByID(context.Background(), deviceID); err != nil {
+			logger.Error("failed to find statsd device",
+				slog.String("deviceID", deviceID),
+				slog.String("error", err.Error()),
+			)
+			os.Exit(1)
+		}
+
+		flushInterval := receiver.DefaultStatsDFlushInterval
+		if val := os.Getenv("MW_STATSD_FLUSH_INTERVAL"); val != "" {
+			flushInterval, err = time.ParseDuration(val)
+			if err != nil || flushInterval <= 0 {
+				logger.Error("invalid statsd flush interval",
+					slog.String("value", val),
+				)
+				os.Exit(1)
+			}
+		}
+
+		allowedNetworks, err := receiver.ParseAllowedNetworks(os.Getenv("MW_STATSD_ALLOWED_CIDRS"))
+		if err != nil {
+			logger.Error("failed to parse statsd allowed cidrs",
+				slog.String("error", err.Error()),
+			)
+			os.Exit(1)
+		}
+
+		statsd := receiver.NewStatsD(server, deviceID, flushInterval, allowedNetworks)
+		go func() {
+			logger.Info("Starting statsd listener...", slog.String("addr", statsdAddr))
+
+			if err := statsd.ListenAndServe(context.Background(), statsdAddr); err != nil {
+				logger.Error(
+					"failed to serve statsd",
+					slog.String("error", err.Error()),
+				)
+				os.Exit(1)
+			}
+		}()
+	}
 
 	logger.Info("Starting server...", slog.String("port", Port))
 
@@ -59,3 +187,32 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// serveHTTP serves handler on addr, with TLS when tlsConfig is set.
+func serveHTTP(logger *slog.Logger, name string, addr string, handler http.Handler, tlsConfig *tls.Config) {
+	// ReadTimeout also bounds bodies, so slow clients don't hold handlers
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Minute,
+	}
+
+	logger.Info("Starting "+name+" server...", slog.String("addr", addr))
+
+	var err error
+	if tlsConfig != nil {
+		// the certificates come from TLSConfig
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error(
+			"failed to serve "+name,
+			slog.String("error", err.Error()),
+		)
+		os.Exit(1)
+	}
+}
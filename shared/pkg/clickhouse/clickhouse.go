@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/google/uuid"
@@ -41,6 +42,48 @@ func (chs *ClickhouseSource) FindDeviceByID(ctx context.Context, deviceID string
 	return &device, nil
 }
 
+// ListDeviceInventories returns the latest inventory of every device that
+// reported one.
+func (chs *ClickhouseSource) ListDeviceInventories(ctx context.Context) ([]*ClickhouseDeviceInventory, error) {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.ListDeviceInventories",
+		trace.WithAttributes(),
+	)
+	defer span.End()
+
+	rows, err := chs.Conn.Query(spanCtx, `SELECT
+		timestamp, device_id, identifier, hostname, os, platform, platform_family, platform_version,
+		kernel_version, arch, cpu_model, cpu_cores, cpu_threads, total_memory, boot_time,
+		interface_names, interface_macs, interface_addresses, agent_version,
+		virtualization_system, virtualization_role
+	FROM device_inventory
+	ORDER BY timestamp DESC
+	LIMIT 1 BY device_id`)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to query")
+
+		return nil, errors.Join(errors.New("failed to query"), err)
+	}
+	defer rows.Close()
+
+	var inventories []*ClickhouseDeviceInventory
+	for rows.Next() {
+		var inventory ClickhouseDeviceInventory
+		if err := rows.ScanStruct(&inventory); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to scan")
+
+			return nil, errors.Join(errors.New("failed to scan"), err)
+		}
+
+		inventories = append(inventories, &inventory)
+	}
+
+	span.SetStatus(codes.Ok, "listed inventories")
+
+	return inventories, nil
+}
+
 func (chs *ClickhouseSource) ListDevices(ctx context.Context) ([]*ClickhouseDevice, error) {
 	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.ListDevices",
 		trace.WithAttributes(),
@@ -74,6 +117,62 @@ func (chs *ClickhouseSource) ListDevices(ctx context.Context) ([]*ClickhouseDevi
 	return devices, nil
 }
 
+// certificateScanWindow is how far back ListExpiringCertificates looks for
+// scans, agents scan hourly by default and at least daily. Locations not
+// scanned since, e.g. removed files or decommissioned devices, drop out.
+const certificateScanWindow = 2 * 24 * time.Hour
+
+// ListExpiringCertificates returns the certificates expiring before the
+// given time, only counting the latest recent scan of every location so
+// renewed certificates drop out. Soonest to expire first.
+func (chs *ClickhouseSource) ListExpiringCertificates(ctx context.Context, before time.Time) ([]*ClickhouseCertificate, error) {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.ListExpiringCertificates",
+		trace.WithAttributes(
+			attribute.String("before", before.Format(time.RFC3339)),
+		),
+	)
+	defer span.End()
+
+	since := time.Now().Add(-certificateScanWindow)
+
+	rows, err := chs.Conn.Query(spanCtx, `SELECT
+		timestamp, device_id, identifier, source, location, fingerprint, subject, issuer, sans, not_before, not_after
+	FROM certificate_telemetries
+	WHERE timestamp > ?
+	AND (device_id, location, timestamp) IN (
+		SELECT device_id, location, max(timestamp)
+		FROM certificate_telemetries
+		WHERE timestamp > ?
+		GROUP BY device_id, location
+	)
+	AND not_after < ?
+	ORDER BY not_after`, since, since, before)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to query")
+
+		return nil, errors.Join(errors.New("failed to query"), err)
+	}
+	defer rows.Close()
+
+	var certificates []*ClickhouseCertificate
+	for rows.Next() {
+		var c ClickhouseCertificate
+		if err := rows.ScanStruct(&c); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to scan")
+
+			return nil, errors.Join(errors.New("failed to scan"), err)
+		}
+
+		certificates = append(certificates, &c)
+	}
+
+	span.SetStatus(codes.Ok, "listed certificates")
+
+	return certificates, nil
+}
+
 func (chs *ClickhouseSource) CreateDevice(ctx context.Context, label string) (*ClickhouseDevice, error) {
 
 	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.CreateDevice",
@@ -39,6 +39,67 @@ func (chs *ClickhouseSource) IngestV1HealthCheck(ctx context.Context, deviceID s
 	return nil
 }
 
+func (chs *ClickhouseSource) IngestV1Inventory(ctx context.Context, deviceID string, healthcheck *v1.HealthCheckRequest) error {
+	inventory := healthcheck.Inventory
+
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1Inventory",
+		trace.WithAttributes(
+			attribute.String("timestamp", healthcheck.Timestamp.AsTime().Format(time.RFC3339)),
+			attribute.String("identifier", healthcheck.Identifier),
+			attribute.String("deviceID", deviceID),
+			attribute.String("agentVersion", inventory.AgentVersion),
+		),
+	)
+	defer span.End()
+
+	names := make([]string, 0, len(inventory.Interfaces))
+	macs := make([]string, 0, len(inventory.Interfaces))
+	addresses := make([][]string, 0, len(inventory.Interfaces))
+	for _, iface := range inventory.Interfaces {
+		names = append(names, iface.Name)
+		macs = append(macs, iface.Mac)
+		addresses = append(addresses, append([]string{}, iface.Addresses...))
+	}
+
+	if err := chs.Conn.Exec(spanCtx, `INSERT INTO device_inventory (
+		timestamp, device_id, identifier, hostname, os, platform, platform_family, platform_version,
+		kernel_version, arch, cpu_model, cpu_cores, cpu_threads, total_memory, boot_time,
+		interface_names, interface_macs, interface_addresses, agent_version,
+		virtualization_system, virtualization_role
+	) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		healthcheck.Timestamp.AsTime(),
+		deviceID,
+		healthcheck.Identifier,
+		inventory.Hostname,
+		inventory.Os,
+		inventory.Platform,
+		inventory.PlatformFamily,
+		inventory.PlatformVersion,
+		inventory.KernelVersion,
+		inventory.Arch,
+		inventory.CpuModel,
+		inventory.CpuCores,
+		inventory.CpuThreads,
+		inventory.TotalMemory,
+		inventory.BootTime.AsTime(),
+		names,
+		macs,
+		addresses,
+		inventory.AgentVersion,
+		inventory.VirtualizationSystem,
+		inventory.VirtualizationRole,
+	); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to insert")
+
+		return errors.Join(errors.New("failed to insert"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
+
 func (chs *ClickhouseSource) IngestV1MemoryTelemetries(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) error {
 	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1MemoryTelemetries",
 		trace.WithAttributes(
@@ -64,6 +125,11 @@ func (chs *ClickhouseSource) IngestV1MemoryTelemetries(ctx context.Context, devi
 	}()
 
 	for _, telemetry := range telemetries {
+		// the memory collector didn't run for this telemetry
+		if telemetry.TotalMemory == 0 {
+			continue
+		}
+
 		_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
 			trace.WithAttributes(
 				attribute.String("timestamp", telemetry.Timestamp.AsTime().Format(time.RFC3339)),
@@ -72,6 +138,8 @@ func (chs *ClickhouseSource) IngestV1MemoryTelemetries(ctx context.Context, devi
 				attribute.Int64("total_memory", int64(telemetry.TotalMemory)),
 				attribute.Int64("free_memory", int64(telemetry.FreeMemory)),
 				attribute.Int64("used_memory", int64(telemetry.UsedMemory)),
+				attribute.Int64("available_memory", int64(telemetry.AvailableMemory)),
+				attribute.Int64("swap_used", int64(telemetry.SwapUsed)),
 			),
 		)
 		defer appendSpan.End()
@@ -83,6 +151,11 @@ func (chs *ClickhouseSource) IngestV1MemoryTelemetries(ctx context.Context, devi
 			telemetry.TotalMemory,
 			telemetry.FreeMemory,
 			telemetry.UsedMemory,
+			telemetry.BuffersMemory,
+			telemetry.CachedMemory,
+			telemetry.AvailableMemory,
+			telemetry.SwapTotal,
+			telemetry.SwapUsed,
 		); err != nil {
 			appendSpan.RecordError(err)
 			appendSpan.SetStatus(codes.Error, "failed to append to batch")
@@ -128,6 +201,11 @@ func (chs *ClickhouseSource) IngestV1CPUTelemetries(ctx context.Context, deviceI
 	}()
 
 	for _, telemetry := range telemetries {
+		// the cpu collector didn't run for this telemetry
+		if telemetry.TotalCpu == 0 {
+			continue
+		}
+
 		_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
 			trace.WithAttributes(
 				attribute.String("timestamp", telemetry.Timestamp.AsTime().Format(time.RFC3339)),
@@ -167,15 +245,15 @@ func (chs *ClickhouseSource) IngestV1CPUTelemetries(ctx context.Context, deviceI
 	return nil
 }
 
-func (chs *ClickhouseSource) IngestV1DisksTelemetries(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) error {
-	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1DisksTelemetries",
+func (chs *ClickhouseSource) IngestV1CPUCoreTelemetries(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1CPUCoreTelemetries",
 		trace.WithAttributes(
 			attribute.String("deviceID", deviceID),
 		),
 	)
 	defer span.End()
 
-	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO disk_telemetries")
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO cpu_core_telemetries")
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "failed to prepare batch")
@@ -192,17 +270,18 @@ func (chs *ClickhouseSource) IngestV1DisksTelemetries(ctx context.Context, devic
 	}()
 
 	for _, telemetry := range telemetries {
-		for _, disk := range telemetry.Disks {
+		for _, cpu := range telemetry.Cpus {
 			_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
 				trace.WithAttributes(
 					attribute.String("timestamp", telemetry.Timestamp.AsTime().Format(time.RFC3339)),
 					attribute.String("deviceID", deviceID),
 					attribute.String("identifier", telemetry.Identifier),
-					attribute.String("label", disk.Label),
-					attribute.String("mountpoint", disk.Mountpoint),
-					attribute.Int64("total_disk", int64(disk.Total)),
-					attribute.Int64("free_disk", int64(disk.Free)),
-					attribute.Int64("used_disk", int64(disk.Used)),
+					attribute.String("name", cpu.Name),
+					attribute.Float64("user", float64(cpu.User)),
+					attribute.Float64("system", float64(cpu.System)),
+					attribute.Float64("iowait", float64(cpu.Iowait)),
+					attribute.Float64("steal", float64(cpu.Steal)),
+					attribute.Float64("idle", float64(cpu.Idle)),
 				),
 			)
 			defer appendSpan.End()
@@ -211,11 +290,12 @@ func (chs *ClickhouseSource) IngestV1DisksTelemetries(ctx context.Context, devic
 				telemetry.Timestamp.AsTime(),
 				deviceID,
 				telemetry.Identifier,
-				disk.Label,
-				disk.Mountpoint,
-				disk.Total,
-				disk.Free,
-				disk.Used,
+				cpu.Name,
+				cpu.User,
+				cpu.System,
+				cpu.Iowait,
+				cpu.Steal,
+				cpu.Idle,
 			); err != nil {
 				appendSpan.RecordError(err)
 				appendSpan.SetStatus(codes.Error, "failed to append to batch")
@@ -223,7 +303,79 @@ func (chs *ClickhouseSource) IngestV1DisksTelemetries(ctx context.Context, devic
 				return errors.Join(errors.New("failed to append to batch"), err)
 			}
 		}
+	}
+
+	if err := batch.Send(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to append to batch")
+
+		return errors.Join(errors.New("failed to send batch"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
+
+func (chs *ClickhouseSource) IngestV1SystemTelemetries(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1SystemTelemetries",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+		),
+	)
+	defer span.End()
+
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO system_telemetries")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to prepare batch")
+
+		return errors.Join(errors.New("failed to prepare batch"), err)
+	}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			span.RecordError(err)
+			chs.Logger.Error("failed to close batch",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	for _, telemetry := range telemetries {
+		system := telemetry.System
+		if system == nil {
+			continue
+		}
+
+		_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
+			trace.WithAttributes(
+				attribute.String("timestamp", telemetry.Timestamp.AsTime().Format(time.RFC3339)),
+				attribute.String("deviceID", deviceID),
+				attribute.String("identifier", telemetry.Identifier),
+				attribute.Float64("load1", system.Load1),
+				attribute.Int64("uptime", int64(system.Uptime)),
+				attribute.Int64("procs_total", int64(system.ProcsTotal)),
+			),
+		)
+		defer appendSpan.End()
+
+		if err := batch.Append(
+			telemetry.Timestamp.AsTime(),
+			deviceID,
+			telemetry.Identifier,
+			system.Load1,
+			system.Load5,
+			system.Load15,
+			system.Uptime,
+			system.ProcsTotal,
+			system.ProcsRunning,
+			system.ProcsBlocked,
+		); err != nil {
+			appendSpan.RecordError(err)
+			appendSpan.SetStatus(codes.Error, "failed to append to batch")
 
+			return errors.Join(errors.New("failed to append to batch"), err)
+		}
 	}
 
 	if err := batch.Send(); err != nil {
@@ -238,15 +390,15 @@ func (chs *ClickhouseSource) IngestV1DisksTelemetries(ctx context.Context, devic
 	return nil
 }
 
-func (chs *ClickhouseSource) IngestV1NetworksTelemetries(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) error {
-	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1NetworksTelemetries",
+func (chs *ClickhouseSource) IngestV1ProcessTelemetries(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1ProcessTelemetries",
 		trace.WithAttributes(
 			attribute.String("deviceID", deviceID),
 		),
 	)
 	defer span.End()
 
-	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO network_telemetries")
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO process_telemetries")
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "failed to prepare batch")
@@ -263,15 +415,16 @@ func (chs *ClickhouseSource) IngestV1NetworksTelemetries(ctx context.Context, de
 	}()
 
 	for _, telemetry := range telemetries {
-		for _, nic := range telemetry.Networks {
+		for _, proc := range telemetry.Processes {
 			_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
 				trace.WithAttributes(
 					attribute.String("timestamp", telemetry.Timestamp.AsTime().Format(time.RFC3339)),
 					attribute.String("deviceID", deviceID),
 					attribute.String("identifier", telemetry.Identifier),
-					attribute.String("name", nic.Name),
-					attribute.Int64("bytes_sent", int64(nic.BytesSent)),
-					attribute.Int64("bytes_received", int64(nic.BytesRecv)),
+					attribute.Int("pid", int(proc.Pid)),
+					attribute.String("name", proc.Name),
+					attribute.Float64("cpu_percent", proc.CpuPercent),
+					attribute.Int64("rss", int64(proc.Rss)),
 				),
 			)
 			defer appendSpan.End()
@@ -280,9 +433,161 @@ func (chs *ClickhouseSource) IngestV1NetworksTelemetries(ctx context.Context, de
 				telemetry.Timestamp.AsTime(),
 				deviceID,
 				telemetry.Identifier,
-				nic.Name,
-				nic.BytesSent,
-				nic.BytesRecv,
+				proc.Pid,
+				proc.Name,
+				proc.CmdlineHash,
+				proc.User,
+				proc.CpuPercent,
+				proc.Rss,
+				proc.Threads,
+				proc.OpenFds,
+			); err != nil {
+				appendSpan.RecordError(err)
+				appendSpan.SetStatus(codes.Error, "failed to append to batch")
+
+				return errors.Join(errors.New("failed to append to batch"), err)
+			}
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to append to batch")
+
+		return errors.Join(errors.New("failed to send batch"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
+
+func (chs *ClickhouseSource) IngestV1SensorTelemetries(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1SensorTelemetries",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+		),
+	)
+	defer span.End()
+
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO sensor_telemetries")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to prepare batch")
+
+		return errors.Join(errors.New("failed to prepare batch"), err)
+	}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			span.RecordError(err)
+			chs.Logger.Error("failed to close batch",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	for _, telemetry := range telemetries {
+		for _, sensor := range telemetry.Sensors {
+			_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
+				trace.WithAttributes(
+					attribute.String("timestamp", telemetry.Timestamp.AsTime().Format(time.RFC3339)),
+					attribute.String("deviceID", deviceID),
+					attribute.String("identifier", telemetry.Identifier),
+					attribute.String("kind", sensor.Kind),
+					attribute.String("chip", sensor.Chip),
+					attribute.String("label", sensor.Label),
+					attribute.Float64("value", sensor.Value),
+				),
+			)
+			defer appendSpan.End()
+
+			if err := batch.Append(
+				telemetry.Timestamp.AsTime(),
+				deviceID,
+				telemetry.Identifier,
+				sensor.Kind,
+				sensor.Chip,
+				sensor.Label,
+				sensor.Value,
+				sensor.High,
+				sensor.Critical,
+			); err != nil {
+				appendSpan.RecordError(err)
+				appendSpan.SetStatus(codes.Error, "failed to append to batch")
+
+				return errors.Join(errors.New("failed to append to batch"), err)
+			}
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to append to batch")
+
+		return errors.Join(errors.New("failed to send batch"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
+
+func (chs *ClickhouseSource) IngestV1CgroupTelemetries(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1CgroupTelemetries",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+		),
+	)
+	defer span.End()
+
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO cgroup_telemetries")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to prepare batch")
+
+		return errors.Join(errors.New("failed to prepare batch"), err)
+	}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			span.RecordError(err)
+			chs.Logger.Error("failed to close batch",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	for _, telemetry := range telemetries {
+		for _, cgroup := range telemetry.Cgroups {
+			_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
+				trace.WithAttributes(
+					attribute.String("timestamp", telemetry.Timestamp.AsTime().Format(time.RFC3339)),
+					attribute.String("deviceID", deviceID),
+					attribute.String("identifier", telemetry.Identifier),
+					attribute.String("path", cgroup.Path),
+					attribute.String("container_id", cgroup.ContainerId),
+					attribute.Float64("cpu_percent", cgroup.CpuPercent),
+					attribute.Int64("memory_current", int64(cgroup.MemoryCurrent)),
+				),
+			)
+			defer appendSpan.End()
+
+			if err := batch.Append(
+				telemetry.Timestamp.AsTime(),
+				deviceID,
+				telemetry.Identifier,
+				cgroup.Path,
+				cgroup.ContainerId,
+				cgroup.CpuUsageUsec,
+				cgroup.CpuPercent,
+				cgroup.ThrottledPeriods,
+				cgroup.MemoryCurrent,
+				cgroup.MemoryMax,
+				cgroup.PidsCurrent,
+				cgroup.PidsMax,
+				cgroup.IoReadBytes,
+				cgroup.IoWriteBytes,
+				cgroup.IoReadBytesPerSec,
+				cgroup.IoWriteBytesPerSec,
 			); err != nil {
 				appendSpan.RecordError(err)
 				appendSpan.SetStatus(codes.Error, "failed to append to batch")
@@ -290,7 +595,657 @@ func (chs *ClickhouseSource) IngestV1NetworksTelemetries(ctx context.Context, de
 				return errors.Join(errors.New("failed to append to batch"), err)
 			}
 		}
+	}
+
+	if err := batch.Send(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to append to batch")
+
+		return errors.Join(errors.New("failed to send batch"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
+
+func (chs *ClickhouseSource) IngestV1CertificateTelemetries(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1CertificateTelemetries",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+		),
+	)
+	defer span.End()
+
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO certificate_telemetries")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to prepare batch")
+
+		return errors.Join(errors.New("failed to prepare batch"), err)
+	}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			span.RecordError(err)
+			chs.Logger.Error("failed to close batch",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
 
+	for _, telemetry := range telemetries {
+		for _, cert := range telemetry.Certificates {
+			_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
+				trace.WithAttributes(
+					attribute.String("timestamp", telemetry.Timestamp.AsTime().Format(time.RFC3339)),
+					attribute.String("deviceID", deviceID),
+					attribute.String("identifier", telemetry.Identifier),
+					attribute.String("location", cert.Location),
+					attribute.String("subject", cert.Subject),
+					attribute.Int("days_remaining", int(cert.DaysRemaining)),
+				),
+			)
+			defer appendSpan.End()
+
+			sans := cert.Sans
+			if sans == nil {
+				sans = []string{}
+			}
+
+			if err := batch.Append(
+				telemetry.Timestamp.AsTime(),
+				deviceID,
+				telemetry.Identifier,
+				cert.Source,
+				cert.Location,
+				cert.Fingerprint,
+				cert.Subject,
+				cert.Issuer,
+				sans,
+				cert.NotBefore.AsTime(),
+				cert.NotAfter.AsTime(),
+				cert.DaysRemaining,
+			); err != nil {
+				appendSpan.RecordError(err)
+				appendSpan.SetStatus(codes.Error, "failed to append to batch")
+
+				return errors.Join(errors.New("failed to append to batch"), err)
+			}
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to append to batch")
+
+		return errors.Join(errors.New("failed to send batch"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
+
+func (chs *ClickhouseSource) IngestV1DisksTelemetries(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1DisksTelemetries",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+		),
+	)
+	defer span.End()
+
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO disk_telemetries")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to prepare batch")
+
+		return errors.Join(errors.New("failed to prepare batch"), err)
+	}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			span.RecordError(err)
+			chs.Logger.Error("failed to close batch",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	for _, telemetry := range telemetries {
+		for _, disk := range telemetry.Disks {
+			_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
+				trace.WithAttributes(
+					attribute.String("timestamp", telemetry.Timestamp.AsTime().Format(time.RFC3339)),
+					attribute.String("deviceID", deviceID),
+					attribute.String("identifier", telemetry.Identifier),
+					attribute.String("label", disk.Label),
+					attribute.String("mountpoint", disk.Mountpoint),
+					attribute.Int64("total_disk", int64(disk.Total)),
+					attribute.Int64("free_disk", int64(disk.Free)),
+					attribute.Int64("used_disk", int64(disk.Used)),
+					attribute.String("fstype", disk.Fstype),
+					attribute.Int64("inodes_used", int64(disk.InodesUsed)),
+				),
+			)
+			defer appendSpan.End()
+
+			if err := batch.Append(
+				telemetry.Timestamp.AsTime(),
+				deviceID,
+				telemetry.Identifier,
+				disk.Label,
+				disk.Mountpoint,
+				disk.Total,
+				disk.Free,
+				disk.Used,
+				disk.Fstype,
+				disk.InodesTotal,
+				disk.InodesUsed,
+				disk.InodesFree,
+			); err != nil {
+				appendSpan.RecordError(err)
+				appendSpan.SetStatus(codes.Error, "failed to append to batch")
+
+				return errors.Join(errors.New("failed to append to batch"), err)
+			}
+		}
+
+	}
+
+	if err := batch.Send(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to append to batch")
+
+		return errors.Join(errors.New("failed to send batch"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
+
+func (chs *ClickhouseSource) IngestV1DiskIOTelemetries(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1DiskIOTelemetries",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+		),
+	)
+	defer span.End()
+
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO disk_io_telemetries")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to prepare batch")
+
+		return errors.Join(errors.New("failed to prepare batch"), err)
+	}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			span.RecordError(err)
+			chs.Logger.Error("failed to close batch",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	for _, telemetry := range telemetries {
+		for _, diskIO := range telemetry.DiskIo {
+			_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
+				trace.WithAttributes(
+					attribute.String("timestamp", telemetry.Timestamp.AsTime().Format(time.RFC3339)),
+					attribute.String("deviceID", deviceID),
+					attribute.String("identifier", telemetry.Identifier),
+					attribute.String("name", diskIO.Name),
+					attribute.Float64("read_bytes_per_sec", diskIO.ReadBytesPerSec),
+					attribute.Float64("write_bytes_per_sec", diskIO.WriteBytesPerSec),
+					attribute.Float64("utilisation", diskIO.Utilisation),
+				),
+			)
+			defer appendSpan.End()
+
+			if err := batch.Append(
+				telemetry.Timestamp.AsTime(),
+				deviceID,
+				telemetry.Identifier,
+				diskIO.Name,
+				diskIO.ReadBytes,
+				diskIO.WriteBytes,
+				diskIO.ReadCount,
+				diskIO.WriteCount,
+				diskIO.IoTime,
+				diskIO.WeightedIoTime,
+				diskIO.IopsInProgress,
+				diskIO.ReadBytesPerSec,
+				diskIO.WriteBytesPerSec,
+				diskIO.ReadsPerSec,
+				diskIO.WritesPerSec,
+				diskIO.Utilisation,
+				diskIO.QueueSize,
+			); err != nil {
+				appendSpan.RecordError(err)
+				appendSpan.SetStatus(codes.Error, "failed to append to batch")
+
+				return errors.Join(errors.New("failed to append to batch"), err)
+			}
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to append to batch")
+
+		return errors.Join(errors.New("failed to send batch"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
+
+func (chs *ClickhouseSource) IngestV1NetworksTelemetries(ctx context.Context, deviceID string, telemetries []*v1.Telemetry) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1NetworksTelemetries",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+		),
+	)
+	defer span.End()
+
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO network_telemetries")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to prepare batch")
+
+		return errors.Join(errors.New("failed to prepare batch"), err)
+	}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			span.RecordError(err)
+			chs.Logger.Error("failed to close batch",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	for _, telemetry := range telemetries {
+		for _, nic := range telemetry.Networks {
+			_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
+				trace.WithAttributes(
+					attribute.String("timestamp", telemetry.Timestamp.AsTime().Format(time.RFC3339)),
+					attribute.String("deviceID", deviceID),
+					attribute.String("identifier", telemetry.Identifier),
+					attribute.String("name", nic.Name),
+					attribute.Int64("bytes_sent", int64(nic.BytesSent)),
+					attribute.Int64("bytes_received", int64(nic.BytesRecv)),
+					attribute.Float64("bytes_sent_per_sec", nic.BytesSentPerSec),
+					attribute.Float64("bytes_received_per_sec", nic.BytesRecvPerSec),
+				),
+			)
+			defer appendSpan.End()
+
+			if err := batch.Append(
+				telemetry.Timestamp.AsTime(),
+				deviceID,
+				telemetry.Identifier,
+				nic.Name,
+				nic.BytesSent,
+				nic.BytesRecv,
+				nic.PacketsSent,
+				nic.PacketsRecv,
+				nic.ErrorsIn,
+				nic.ErrorsOut,
+				nic.DropsIn,
+				nic.DropsOut,
+				nic.BytesSentPerSec,
+				nic.BytesRecvPerSec,
+				nic.PacketsSentPerSec,
+				nic.PacketsRecvPerSec,
+			); err != nil {
+				appendSpan.RecordError(err)
+				appendSpan.SetStatus(codes.Error, "failed to append to batch")
+
+				return errors.Join(errors.New("failed to append to batch"), err)
+			}
+		}
+
+	}
+
+	if err := batch.Send(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to append to batch")
+
+		return errors.Join(errors.New("failed to send batch"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
+
+func (chs *ClickhouseSource) IngestV1CustomMetrics(ctx context.Context, deviceID string, req *v1.SendMetricsRequest) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1CustomMetrics",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+			attribute.String("identifier", req.Identifier),
+		),
+	)
+	defer span.End()
+
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO custom_metrics")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to prepare batch")
+
+		return errors.Join(errors.New("failed to prepare batch"), err)
+	}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			span.RecordError(err)
+			chs.Logger.Error("failed to close batch",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	for _, metric := range req.Metrics {
+		_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
+			trace.WithAttributes(
+				attribute.String("timestamp", metric.Timestamp.AsTime().Format(time.RFC3339)),
+				attribute.String("deviceID", deviceID),
+				attribute.String("identifier", req.Identifier),
+				attribute.String("source", metric.Source),
+				attribute.String("name", metric.Name),
+				attribute.Float64("value", metric.Value),
+			),
+		)
+		defer appendSpan.End()
+
+		labels := metric.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+
+		if err := batch.Append(
+			metric.Timestamp.AsTime(),
+			deviceID,
+			req.Identifier,
+			metric.Source,
+			metric.Name,
+			metric.Type,
+			metric.Value,
+			labels,
+		); err != nil {
+			appendSpan.RecordError(err)
+			appendSpan.SetStatus(codes.Error, "failed to append to batch")
+
+			return errors.Join(errors.New("failed to append to batch"), err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to append to batch")
+
+		return errors.Join(errors.New("failed to send batch"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
+
+func (chs *ClickhouseSource) IngestV1Logs(ctx context.Context, deviceID string, req *v1.SendLogsRequest) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1Logs",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+			attribute.String("identifier", req.Identifier),
+		),
+	)
+	defer span.End()
+
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO device_logs")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to prepare batch")
+
+		return errors.Join(errors.New("failed to prepare batch"), err)
+	}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			span.RecordError(err)
+			chs.Logger.Error("failed to close batch",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	for _, entry := range req.Entries {
+		_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
+			trace.WithAttributes(
+				attribute.String("timestamp", entry.Timestamp.AsTime().Format(time.RFC3339)),
+				attribute.String("deviceID", deviceID),
+				attribute.String("identifier", req.Identifier),
+				attribute.String("source", entry.Source),
+				attribute.String("path", entry.Path),
+				attribute.String("severity", entry.Severity),
+			),
+		)
+		defer appendSpan.End()
+
+		fields := entry.Fields
+		if fields == nil {
+			fields = map[string]string{}
+		}
+
+		if err := batch.Append(
+			entry.Timestamp.AsTime(),
+			deviceID,
+			req.Identifier,
+			entry.Source,
+			entry.Path,
+			entry.Severity,
+			entry.Message,
+			fields,
+		); err != nil {
+			appendSpan.RecordError(err)
+			appendSpan.SetStatus(codes.Error, "failed to append to batch")
+
+			return errors.Join(errors.New("failed to append to batch"), err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to append to batch")
+
+		return errors.Join(errors.New("failed to send batch"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
+
+func (chs *ClickhouseSource) IngestV1ProbeResults(ctx context.Context, deviceID string, req *v1.SendProbeResultsRequest) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1ProbeResults",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+			attribute.String("identifier", req.Identifier),
+		),
+	)
+	defer span.End()
+
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO probe_results")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to prepare batch")
+
+		return errors.Join(errors.New("failed to prepare batch"), err)
+	}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			span.RecordError(err)
+			chs.Logger.Error("failed to close batch",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	for _, result := range req.Results {
+		_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
+			trace.WithAttributes(
+				attribute.String("timestamp", result.Timestamp.AsTime().Format(time.RFC3339)),
+				attribute.String("deviceID", deviceID),
+				attribute.String("identifier", req.Identifier),
+				attribute.String("name", result.Name),
+				attribute.String("kind", result.Kind),
+				attribute.Bool("success", result.Success),
+			),
+		)
+		defer appendSpan.End()
+
+		var certNotAfter *time.Time
+		if result.CertNotAfter != nil {
+			notAfter := result.CertNotAfter.AsTime()
+			certNotAfter = &notAfter
+		}
+
+		if err := batch.Append(
+			result.Timestamp.AsTime(),
+			deviceID,
+			req.Identifier,
+			result.Name,
+			result.Kind,
+			result.Target,
+			result.Success,
+			result.LatencyMs,
+			result.StatusCode,
+			certNotAfter,
+			result.Error,
+		); err != nil {
+			appendSpan.RecordError(err)
+			appendSpan.SetStatus(codes.Error, "failed to append to batch")
+
+			return errors.Join(errors.New("failed to append to batch"), err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to append to batch")
+
+		return errors.Join(errors.New("failed to send batch"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
+
+// LastUnitEvents returns the latest event of every unit of a device, keyed by
+// unit name.
+func (chs *ClickhouseSource) LastUnitEvents(ctx context.Context, deviceID string) (map[string]*ClickhouseUnitEvent, error) {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.LastUnitEvents",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+		),
+	)
+	defer span.End()
+
+	rows, err := chs.Conn.Query(spanCtx, `SELECT
+		max(timestamp) AS timestamp,
+		argMax(identifier, timestamp) AS identifier,
+		unit,
+		argMax(previous_active_state, timestamp) AS previous_active_state,
+		argMax(previous_sub_state, timestamp) AS previous_sub_state,
+		argMax(load_state, timestamp) AS load_state,
+		argMax(active_state, timestamp) AS active_state,
+		argMax(sub_state, timestamp) AS sub_state,
+		argMax(restarts, timestamp) AS restarts,
+		argMax(main_pid, timestamp) AS main_pid
+	FROM unit_events
+	WHERE device_id = ?
+	GROUP BY unit`, deviceID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to query")
+
+		return nil, errors.Join(errors.New("failed to query"), err)
+	}
+	defer rows.Close()
+
+	events := make(map[string]*ClickhouseUnitEvent)
+	for rows.Next() {
+		var event ClickhouseUnitEvent
+		if err := rows.ScanStruct(&event); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to scan")
+
+			return nil, errors.Join(errors.New("failed to scan"), err)
+		}
+
+		events[event.Unit] = &event
+	}
+
+	span.SetStatus(codes.Ok, "found unit events")
+
+	return events, nil
+}
+
+func (chs *ClickhouseSource) IngestV1UnitEvents(ctx context.Context, deviceID string, events []*ClickhouseUnitEvent) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV1UnitEvents",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+			attribute.Int("events", len(events)),
+		),
+	)
+	defer span.End()
+
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO unit_events")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to prepare batch")
+
+		return errors.Join(errors.New("failed to prepare batch"), err)
+	}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			span.RecordError(err)
+			chs.Logger.Error("failed to close batch",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	for _, event := range events {
+		_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
+			trace.WithAttributes(
+				attribute.String("timestamp", event.Timestamp.Format(time.RFC3339)),
+				attribute.String("deviceID", deviceID),
+				attribute.String("identifier", event.Identifier),
+				attribute.String("unit", event.Unit),
+				attribute.String("activeState", event.ActiveState),
+				attribute.String("subState", event.SubState),
+			),
+		)
+		defer appendSpan.End()
+
+		if err := batch.Append(
+			event.Timestamp,
+			deviceID,
+			event.Identifier,
+			event.Unit,
+			event.PreviousActiveState,
+			event.PreviousSubState,
+			event.LoadState,
+			event.ActiveState,
+			event.SubState,
+			event.Restarts,
+			event.MainPID,
+		); err != nil {
+			appendSpan.RecordError(err)
+			appendSpan.SetStatus(codes.Error, "failed to append to batch")
+
+			return errors.Join(errors.New("failed to append to batch"), err)
+		}
 	}
 
 	if err := batch.Send(); err != nil {
@@ -0,0 +1,136 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	v2 "github.com/microwatcher/shared/pkg/gen/microwatcher/v2"
+	"github.com/microwatcher/shared/pkg/otlp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var metricTypes = map[v2.MetricType]string{
+	v2.MetricType_METRIC_TYPE_GAUGE:     "gauge",
+	v2.MetricType_METRIC_TYPE_COUNTER:   "counter",
+	v2.MetricType_METRIC_TYPE_HISTOGRAM: "histogram",
+}
+
+// ErrInvalidMetric is returned for metrics that can't be stored, unlike a
+// failed insert sending them again won't help.
+var ErrInvalidMetric = errors.New("invalid metric")
+
+// ValidateV2Metric returns why metric can't be stored, nil when it can.
+func ValidateV2Metric(metric *v2.Metric) error {
+	if metric.Name == "" {
+		return fmt.Errorf("%w: no name", ErrInvalidMetric)
+	}
+	if _, ok := metricTypes[metric.Type]; !ok {
+		return fmt.Errorf("%w %q: unsupported type %s", ErrInvalidMetric, metric.Name, metric.Type)
+	}
+
+	return nil
+}
+
+// IngestV2Metrics stores generic metrics in the single metrics table, every
+// kind of measurement lands there with its labels. Receivers for other
+// protocols map onto v2 metrics to share it.
+func (chs *ClickhouseSource) IngestV2Metrics(ctx context.Context, deviceID string, identifier string, metrics []*v2.Metric) error {
+	spanCtx, span := otlp.IngestTracer.Start(ctx, "ClickhouseSource.IngestV2Metrics",
+		trace.WithAttributes(
+			attribute.String("deviceID", deviceID),
+			attribute.String("identifier", identifier),
+		),
+	)
+	defer span.End()
+
+	// the whole batch is checked first, ErrInvalidMetric tells the caller
+	// the payload is at fault and not clickhouse
+	for _, metric := range metrics {
+		if err := ValidateV2Metric(metric); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "invalid metric")
+
+			return err
+		}
+	}
+
+	batch, err := chs.Conn.PrepareBatch(spanCtx, "INSERT INTO metrics")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to prepare batch")
+
+		return errors.Join(errors.New("failed to prepare batch"), err)
+	}
+	defer func() {
+		if err := batch.Close(); err != nil {
+			span.RecordError(err)
+			chs.Logger.Error("failed to close batch",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	for _, metric := range metrics {
+		_, appendSpan := otlp.IngestTracer.Start(ctx, "appending to batch",
+			trace.WithAttributes(
+				attribute.String("timestamp", metric.Timestamp.AsTime().Format(time.RFC3339)),
+				attribute.String("deviceID", deviceID),
+				attribute.String("identifier", identifier),
+				attribute.String("name", metric.Name),
+				attribute.String("type", metric.Type.String()),
+			),
+		)
+		defer appendSpan.End()
+
+		kind := metricTypes[metric.Type]
+
+		labels := metric.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+
+		histogram := metric.GetHistogram()
+		bounds := make([]float64, len(histogram.GetBuckets()))
+		counts := make([]uint64, len(histogram.GetBuckets()))
+		for idx, bucket := range histogram.GetBuckets() {
+			bounds[idx] = bucket.UpperBound
+			counts[idx] = bucket.Count
+		}
+
+		if err := batch.Append(
+			metric.Timestamp.AsTime(),
+			deviceID,
+			identifier,
+			metric.Name,
+			kind,
+			metric.Unit,
+			labels,
+			metric.GetValue(),
+			bounds,
+			counts,
+			histogram.GetSum(),
+			histogram.GetCount(),
+		); err != nil {
+			appendSpan.RecordError(err)
+			appendSpan.SetStatus(codes.Error, "failed to append to batch")
+
+			return errors.Join(errors.New("failed to append to batch"), err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to append to batch")
+
+		return errors.Join(errors.New("failed to send batch"), err)
+	}
+
+	span.SetStatus(codes.Ok, "ingested")
+
+	return nil
+}
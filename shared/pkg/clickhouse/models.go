@@ -1,6 +1,10 @@
 package clickhouse
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type ClickhouseDevice struct {
 	ID      uuid.UUID `ch:"id"`
@@ -8,3 +12,60 @@ type ClickhouseDevice struct {
 	Secret  string    `ch:"secret"`
 	Version int32     `ch:"version"`
 }
+
+// ClickhouseUnitEvent is a systemd unit changing state, previous states are
+// empty the first time a unit is seen.
+type ClickhouseUnitEvent struct {
+	Timestamp           time.Time `ch:"timestamp"`
+	Identifier          string    `ch:"identifier"`
+	Unit                string    `ch:"unit"`
+	PreviousActiveState string    `ch:"previous_active_state"`
+	PreviousSubState    string    `ch:"previous_sub_state"`
+	LoadState           string    `ch:"load_state"`
+	ActiveState         string    `ch:"active_state"`
+	SubState            string    `ch:"sub_state"`
+	Restarts            uint32    `ch:"restarts"`
+	MainPID             int32     `ch:"main_pid"`
+}
+
+// ClickhouseCertificate is a certificate as of the latest scan of its
+// location.
+type ClickhouseCertificate struct {
+	Timestamp   time.Time `ch:"timestamp"`
+	DeviceID    uuid.UUID `ch:"device_id"`
+	Identifier  string    `ch:"identifier"`
+	Source      string    `ch:"source"`
+	Location    string    `ch:"location"`
+	Fingerprint string    `ch:"fingerprint"`
+	Subject     string    `ch:"subject"`
+	Issuer      string    `ch:"issuer"`
+	SANs        []string  `ch:"sans"`
+	NotBefore   time.Time `ch:"not_before"`
+	NotAfter    time.Time `ch:"not_after"`
+}
+
+// ClickhouseDeviceInventory is the latest inventory a device reported,
+// interfaces are spread over the interface_* arrays.
+type ClickhouseDeviceInventory struct {
+	Timestamp            time.Time  `ch:"timestamp"`
+	DeviceID             uuid.UUID  `ch:"device_id"`
+	Identifier           string     `ch:"identifier"`
+	Hostname             string     `ch:"hostname"`
+	OS                   string     `ch:"os"`
+	Platform             string     `ch:"platform"`
+	PlatformFamily       string     `ch:"platform_family"`
+	PlatformVersion      string     `ch:"platform_version"`
+	KernelVersion        string     `ch:"kernel_version"`
+	Arch                 string     `ch:"arch"`
+	CPUModel             string     `ch:"cpu_model"`
+	CPUCores             int32      `ch:"cpu_cores"`
+	CPUThreads           int32      `ch:"cpu_threads"`
+	TotalMemory          uint64     `ch:"total_memory"`
+	BootTime             time.Time  `ch:"boot_time"`
+	InterfaceNames       []string   `ch:"interface_names"`
+	InterfaceMACs        []string   `ch:"interface_macs"`
+	InterfaceAddresses   [][]string `ch:"interface_addresses"`
+	AgentVersion         string     `ch:"agent_version"`
+	VirtualizationSystem string     `ch:"virtualization_system"`
+	VirtualizationRole   string     `ch:"virtualization_role"`
+}
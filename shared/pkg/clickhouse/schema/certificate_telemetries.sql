@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS certificate_telemetries
+(
+    timestamp      DateTime64(3),
+    device_id      UUID,
+    identifier     String,
+    source         LowCardinality(String),
+    location       String,
+    fingerprint    String,
+    subject        String,
+    issuer         String,
+    sans           Array(String),
+    not_before     DateTime64(3),
+    not_after      DateTime64(3),
+    days_remaining Int32
+)
+ENGINE = MergeTree
+ORDER BY (device_id, location, timestamp);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS cgroup_telemetries
+(
+    timestamp              DateTime64(3),
+    device_id              UUID,
+    identifier             String,
+    path                   String,
+    container_id           String,
+    cpu_usage_usec         UInt64,
+    cpu_percent            Float64,
+    throttled_periods      UInt64,
+    memory_current         UInt64,
+    memory_max             UInt64,
+    pids_current           UInt64,
+    pids_max               UInt64,
+    io_read_bytes          UInt64,
+    io_write_bytes         UInt64,
+    io_read_bytes_per_sec  Float64,
+    io_write_bytes_per_sec Float64
+)
+ENGINE = MergeTree
+ORDER BY (device_id, path, timestamp);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS cpu_core_telemetries
+(
+    timestamp  DateTime64(3),
+    device_id  UUID,
+    identifier String,
+    name       LowCardinality(String),
+    user       Float32,
+    system     Float32,
+    iowait     Float32,
+    steal      Float32,
+    idle       Float32
+)
+ENGINE = MergeTree
+ORDER BY (device_id, name, timestamp);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS custom_metrics
+(
+    timestamp  DateTime64(3),
+    device_id  UUID,
+    identifier String,
+    source     LowCardinality(String),
+    name       LowCardinality(String),
+    type       LowCardinality(String),
+    value      Float64,
+    labels     Map(String, String)
+)
+ENGINE = MergeTree
+ORDER BY (device_id, name, timestamp);
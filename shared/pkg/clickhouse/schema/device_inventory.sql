@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS device_inventory
+(
+    timestamp             DateTime64(3),
+    device_id             UUID,
+    identifier            String,
+    hostname              String,
+    os                    LowCardinality(String),
+    platform              LowCardinality(String),
+    platform_family       LowCardinality(String),
+    platform_version      LowCardinality(String),
+    kernel_version        LowCardinality(String),
+    arch                  LowCardinality(String),
+    cpu_model             LowCardinality(String),
+    cpu_cores             Int32,
+    cpu_threads           Int32,
+    total_memory          UInt64,
+    boot_time             DateTime,
+    interface_names       Array(String),
+    interface_macs        Array(String),
+    interface_addresses   Array(Array(String)),
+    agent_version         LowCardinality(String),
+    virtualization_system LowCardinality(String),
+    virtualization_role   LowCardinality(String)
+)
+ENGINE = MergeTree
+ORDER BY (device_id, timestamp);
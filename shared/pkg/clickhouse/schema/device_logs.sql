@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS device_logs
+(
+    timestamp  DateTime64(3),
+    device_id  UUID,
+    identifier String,
+    source     LowCardinality(String),
+    path       LowCardinality(String),
+    severity   LowCardinality(String),
+    message    String,
+    fields     Map(LowCardinality(String), String)
+)
+ENGINE = MergeTree
+ORDER BY (device_id, timestamp);
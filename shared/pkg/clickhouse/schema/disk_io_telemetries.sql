@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS disk_io_telemetries
+(
+    timestamp           DateTime64(3),
+    device_id           UUID,
+    identifier          String,
+    name                LowCardinality(String),
+    read_bytes          UInt64,
+    write_bytes         UInt64,
+    read_count          UInt64,
+    write_count         UInt64,
+    io_time             UInt64,
+    weighted_io_time    UInt64,
+    iops_in_progress    UInt64,
+    read_bytes_per_sec  Float64,
+    write_bytes_per_sec Float64,
+    reads_per_sec       Float64,
+    writes_per_sec      Float64,
+    utilisation         Float64,
+    queue_size          Float64
+)
+ENGINE = MergeTree
+ORDER BY (device_id, name, timestamp);
@@ -0,0 +1,5 @@
+ALTER TABLE disk_telemetries
+    ADD COLUMN IF NOT EXISTS fstype       LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS inodes_total UInt64,
+    ADD COLUMN IF NOT EXISTS inodes_used  UInt64,
+    ADD COLUMN IF NOT EXISTS inodes_free  UInt64;
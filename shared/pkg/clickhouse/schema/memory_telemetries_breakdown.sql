@@ -0,0 +1,6 @@
+ALTER TABLE memory_telemetries
+    ADD COLUMN IF NOT EXISTS buffers_memory   UInt64,
+    ADD COLUMN IF NOT EXISTS cached_memory    UInt64,
+    ADD COLUMN IF NOT EXISTS available_memory UInt64,
+    ADD COLUMN IF NOT EXISTS swap_total       UInt64,
+    ADD COLUMN IF NOT EXISTS swap_used        UInt64;
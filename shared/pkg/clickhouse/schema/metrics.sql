@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS metrics
+(
+    timestamp     DateTime64(3),
+    device_id     UUID,
+    identifier    String,
+    name          LowCardinality(String),
+    type          LowCardinality(String),
+    unit          LowCardinality(String),
+    labels        Map(LowCardinality(String), String),
+    value         Float64,
+    -- histograms only, bucket_counts are cumulative
+    bucket_bounds Array(Float64),
+    bucket_counts Array(UInt64),
+    sum           Float64,
+    count         UInt64
+)
+ENGINE = MergeTree
+ORDER BY (device_id, name, timestamp);
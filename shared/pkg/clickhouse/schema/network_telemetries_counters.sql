@@ -0,0 +1,11 @@
+ALTER TABLE network_telemetries
+    ADD COLUMN IF NOT EXISTS packets_sent         UInt64,
+    ADD COLUMN IF NOT EXISTS packets_recv         UInt64,
+    ADD COLUMN IF NOT EXISTS errors_in            UInt64,
+    ADD COLUMN IF NOT EXISTS errors_out           UInt64,
+    ADD COLUMN IF NOT EXISTS drops_in             UInt64,
+    ADD COLUMN IF NOT EXISTS drops_out            UInt64,
+    ADD COLUMN IF NOT EXISTS bytes_sent_per_sec   Float64,
+    ADD COLUMN IF NOT EXISTS bytes_recv_per_sec   Float64,
+    ADD COLUMN IF NOT EXISTS packets_sent_per_sec Float64,
+    ADD COLUMN IF NOT EXISTS packets_recv_per_sec Float64;
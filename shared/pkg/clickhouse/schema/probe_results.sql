@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS probe_results
+(
+    timestamp      DateTime64(3),
+    device_id      UUID,
+    identifier     String,
+    name           LowCardinality(String),
+    kind           LowCardinality(String),
+    target         String,
+    success        Bool,
+    latency_ms     Float64,
+    status_code    Int32,
+    cert_not_after Nullable(DateTime64(3)),
+    error          String
+)
+ENGINE = MergeTree
+ORDER BY (device_id, name, timestamp);
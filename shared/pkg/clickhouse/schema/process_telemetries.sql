@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS process_telemetries
+(
+    timestamp    DateTime64(3),
+    device_id    UUID,
+    identifier   String,
+    pid          Int32,
+    name         LowCardinality(String),
+    cmdline_hash String,
+    user         LowCardinality(String),
+    cpu_percent  Float64,
+    rss          UInt64,
+    threads      Int32,
+    open_fds     Int32
+)
+ENGINE = MergeTree
+ORDER BY (device_id, timestamp, pid);
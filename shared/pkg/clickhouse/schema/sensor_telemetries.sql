@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS sensor_telemetries
+(
+    timestamp  DateTime64(3),
+    device_id  UUID,
+    identifier String,
+    kind       LowCardinality(String),
+    chip       LowCardinality(String),
+    label      LowCardinality(String),
+    value      Float64,
+    high       Float64,
+    critical   Float64
+)
+ENGINE = MergeTree
+ORDER BY (device_id, kind, chip, label, timestamp);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS system_telemetries
+(
+    timestamp     DateTime64(3),
+    device_id     UUID,
+    identifier    String,
+    load1         Float64,
+    load5         Float64,
+    load15        Float64,
+    uptime        UInt64,
+    procs_total   UInt64,
+    procs_running UInt64,
+    procs_blocked UInt64
+)
+ENGINE = MergeTree
+ORDER BY (device_id, timestamp);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS unit_events
+(
+    timestamp             DateTime64(3),
+    device_id             UUID,
+    identifier            String,
+    unit                  LowCardinality(String),
+    previous_active_state LowCardinality(String),
+    previous_sub_state    LowCardinality(String),
+    load_state            LowCardinality(String),
+    active_state          LowCardinality(String),
+    sub_state             LowCardinality(String),
+    restarts              UInt32,
+    main_pid              Int32
+)
+ENGINE = MergeTree
+ORDER BY (device_id, unit, timestamp);
@@ -134,12 +134,26 @@ func (*PingResponse) Descriptor() ([]byte, []int) {
 
 // === TELEMETRY ===
 type TelemetryNetwork struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	BytesSent     uint64                 `protobuf:"varint,2,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`
-	BytesRecv     uint64                 `protobuf:"varint,3,opt,name=bytes_recv,json=bytesRecv,proto3" json:"bytes_recv,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// interface name
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// counters are cumulative since the interface came up
+	BytesSent   uint64 `protobuf:"varint,2,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`
+	BytesRecv   uint64 `protobuf:"varint,3,opt,name=bytes_recv,json=bytesRecv,proto3" json:"bytes_recv,omitempty"`
+	PacketsSent uint64 `protobuf:"varint,4,opt,name=packets_sent,json=packetsSent,proto3" json:"packets_sent,omitempty"`
+	PacketsRecv uint64 `protobuf:"varint,5,opt,name=packets_recv,json=packetsRecv,proto3" json:"packets_recv,omitempty"`
+	ErrorsIn    uint64 `protobuf:"varint,6,opt,name=errors_in,json=errorsIn,proto3" json:"errors_in,omitempty"`
+	ErrorsOut   uint64 `protobuf:"varint,7,opt,name=errors_out,json=errorsOut,proto3" json:"errors_out,omitempty"`
+	DropsIn     uint64 `protobuf:"varint,8,opt,name=drops_in,json=dropsIn,proto3" json:"drops_in,omitempty"`
+	DropsOut    uint64 `protobuf:"varint,9,opt,name=drops_out,json=dropsOut,proto3" json:"drops_out,omitempty"`
+	// rates since the previous sample, zero on the first sample of an
+	// interface and after its counters were reset
+	BytesSentPerSec   float64 `protobuf:"fixed64,10,opt,name=bytes_sent_per_sec,json=bytesSentPerSec,proto3" json:"bytes_sent_per_sec,omitempty"`
+	BytesRecvPerSec   float64 `protobuf:"fixed64,11,opt,name=bytes_recv_per_sec,json=bytesRecvPerSec,proto3" json:"bytes_recv_per_sec,omitempty"`
+	PacketsSentPerSec float64 `protobuf:"fixed64,12,opt,name=packets_sent_per_sec,json=packetsSentPerSec,proto3" json:"packets_sent_per_sec,omitempty"`
+	PacketsRecvPerSec float64 `protobuf:"fixed64,13,opt,name=packets_recv_per_sec,json=packetsRecvPerSec,proto3" json:"packets_recv_per_sec,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *TelemetryNetwork) Reset() {
@@ -193,6 +207,76 @@ func (x *TelemetryNetwork) GetBytesRecv() uint64 {
 	return 0
 }
 
+func (x *TelemetryNetwork) GetPacketsSent() uint64 {
+	if x != nil {
+		return x.PacketsSent
+	}
+	return 0
+}
+
+func (x *TelemetryNetwork) GetPacketsRecv() uint64 {
+	if x != nil {
+		return x.PacketsRecv
+	}
+	return 0
+}
+
+func (x *TelemetryNetwork) GetErrorsIn() uint64 {
+	if x != nil {
+		return x.ErrorsIn
+	}
+	return 0
+}
+
+func (x *TelemetryNetwork) GetErrorsOut() uint64 {
+	if x != nil {
+		return x.ErrorsOut
+	}
+	return 0
+}
+
+func (x *TelemetryNetwork) GetDropsIn() uint64 {
+	if x != nil {
+		return x.DropsIn
+	}
+	return 0
+}
+
+func (x *TelemetryNetwork) GetDropsOut() uint64 {
+	if x != nil {
+		return x.DropsOut
+	}
+	return 0
+}
+
+func (x *TelemetryNetwork) GetBytesSentPerSec() float64 {
+	if x != nil {
+		return x.BytesSentPerSec
+	}
+	return 0
+}
+
+func (x *TelemetryNetwork) GetBytesRecvPerSec() float64 {
+	if x != nil {
+		return x.BytesRecvPerSec
+	}
+	return 0
+}
+
+func (x *TelemetryNetwork) GetPacketsSentPerSec() float64 {
+	if x != nil {
+		return x.PacketsSentPerSec
+	}
+	return 0
+}
+
+func (x *TelemetryNetwork) GetPacketsRecvPerSec() float64 {
+	if x != nil {
+		return x.PacketsRecvPerSec
+	}
+	return 0
+}
+
 type TelemetryDisk struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Label         string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
@@ -200,6 +284,10 @@ type TelemetryDisk struct {
 	Total         uint64                 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
 	Free          uint64                 `protobuf:"varint,4,opt,name=free,proto3" json:"free,omitempty"`
 	Used          uint64                 `protobuf:"varint,5,opt,name=used,proto3" json:"used,omitempty"`
+	Fstype        string                 `protobuf:"bytes,6,opt,name=fstype,proto3" json:"fstype,omitempty"`
+	InodesTotal   uint64                 `protobuf:"varint,7,opt,name=inodes_total,json=inodesTotal,proto3" json:"inodes_total,omitempty"`
+	InodesUsed    uint64                 `protobuf:"varint,8,opt,name=inodes_used,json=inodesUsed,proto3" json:"inodes_used,omitempty"`
+	InodesFree    uint64                 `protobuf:"varint,9,opt,name=inodes_free,json=inodesFree,proto3" json:"inodes_free,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -269,36 +357,76 @@ func (x *TelemetryDisk) GetUsed() uint64 {
 	return 0
 }
 
-type Telemetry struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Identifier    string                 `protobuf:"bytes,2,opt,name=identifier,proto3" json:"identifier,omitempty"`
-	TotalMemory   uint64                 `protobuf:"varint,3,opt,name=total_memory,json=totalMemory,proto3" json:"total_memory,omitempty"`
-	FreeMemory    uint64                 `protobuf:"varint,4,opt,name=free_memory,json=freeMemory,proto3" json:"free_memory,omitempty"`
-	UsedMemory    uint64                 `protobuf:"varint,5,opt,name=used_memory,json=usedMemory,proto3" json:"used_memory,omitempty"`
-	TotalCpu      float32                `protobuf:"fixed32,6,opt,name=total_cpu,json=totalCpu,proto3" json:"total_cpu,omitempty"`
-	FreeCpu       float32                `protobuf:"fixed32,7,opt,name=free_cpu,json=freeCpu,proto3" json:"free_cpu,omitempty"`
-	UsedCpu       float32                `protobuf:"fixed32,8,opt,name=used_cpu,json=usedCpu,proto3" json:"used_cpu,omitempty"`
-	Disks         []*TelemetryDisk       `protobuf:"bytes,9,rep,name=disks,proto3" json:"disks,omitempty"`
-	Networks      []*TelemetryNetwork    `protobuf:"bytes,10,rep,name=networks,proto3" json:"networks,omitempty"`
+func (x *TelemetryDisk) GetFstype() string {
+	if x != nil {
+		return x.Fstype
+	}
+	return ""
+}
+
+func (x *TelemetryDisk) GetInodesTotal() uint64 {
+	if x != nil {
+		return x.InodesTotal
+	}
+	return 0
+}
+
+func (x *TelemetryDisk) GetInodesUsed() uint64 {
+	if x != nil {
+		return x.InodesUsed
+	}
+	return 0
+}
+
+func (x *TelemetryDisk) GetInodesFree() uint64 {
+	if x != nil {
+		return x.InodesFree
+	}
+	return 0
+}
+
+// block device activity, counters are cumulative since boot
+type TelemetryDiskIO struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// device name, e.g. "sda" or "nvme0n1"
+	Name       string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ReadBytes  uint64 `protobuf:"varint,2,opt,name=read_bytes,json=readBytes,proto3" json:"read_bytes,omitempty"`
+	WriteBytes uint64 `protobuf:"varint,3,opt,name=write_bytes,json=writeBytes,proto3" json:"write_bytes,omitempty"`
+	ReadCount  uint64 `protobuf:"varint,4,opt,name=read_count,json=readCount,proto3" json:"read_count,omitempty"`
+	WriteCount uint64 `protobuf:"varint,5,opt,name=write_count,json=writeCount,proto3" json:"write_count,omitempty"`
+	// milliseconds spent doing I/O
+	IoTime uint64 `protobuf:"varint,6,opt,name=io_time,json=ioTime,proto3" json:"io_time,omitempty"`
+	// milliseconds spent doing I/O, weighted by the number of queued requests
+	WeightedIoTime uint64 `protobuf:"varint,7,opt,name=weighted_io_time,json=weightedIoTime,proto3" json:"weighted_io_time,omitempty"`
+	IopsInProgress uint64 `protobuf:"varint,8,opt,name=iops_in_progress,json=iopsInProgress,proto3" json:"iops_in_progress,omitempty"`
+	// rates since the previous sample, zero on the first sample of a device
+	// and after its counters were reset
+	ReadBytesPerSec  float64 `protobuf:"fixed64,9,opt,name=read_bytes_per_sec,json=readBytesPerSec,proto3" json:"read_bytes_per_sec,omitempty"`
+	WriteBytesPerSec float64 `protobuf:"fixed64,10,opt,name=write_bytes_per_sec,json=writeBytesPerSec,proto3" json:"write_bytes_per_sec,omitempty"`
+	ReadsPerSec      float64 `protobuf:"fixed64,11,opt,name=reads_per_sec,json=readsPerSec,proto3" json:"reads_per_sec,omitempty"`
+	WritesPerSec     float64 `protobuf:"fixed64,12,opt,name=writes_per_sec,json=writesPerSec,proto3" json:"writes_per_sec,omitempty"`
+	// percentage of the time the device was busy
+	Utilisation float64 `protobuf:"fixed64,13,opt,name=utilisation,proto3" json:"utilisation,omitempty"`
+	// average number of requests in queue
+	QueueSize     float64 `protobuf:"fixed64,14,opt,name=queue_size,json=queueSize,proto3" json:"queue_size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Telemetry) Reset() {
-	*x = Telemetry{}
+func (x *TelemetryDiskIO) Reset() {
+	*x = TelemetryDiskIO{}
 	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Telemetry) String() string {
+func (x *TelemetryDiskIO) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Telemetry) ProtoMessage() {}
+func (*TelemetryDiskIO) ProtoMessage() {}
 
-func (x *Telemetry) ProtoReflect() protoreflect.Message {
+func (x *TelemetryDiskIO) ProtoReflect() protoreflect.Message {
 	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -310,102 +438,139 @@ func (x *Telemetry) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Telemetry.ProtoReflect.Descriptor instead.
-func (*Telemetry) Descriptor() ([]byte, []int) {
+// Deprecated: Use TelemetryDiskIO.ProtoReflect.Descriptor instead.
+func (*TelemetryDiskIO) Descriptor() ([]byte, []int) {
 	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Telemetry) GetTimestamp() *timestamppb.Timestamp {
+func (x *TelemetryDiskIO) GetName() string {
 	if x != nil {
-		return x.Timestamp
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *Telemetry) GetIdentifier() string {
+func (x *TelemetryDiskIO) GetReadBytes() uint64 {
 	if x != nil {
-		return x.Identifier
+		return x.ReadBytes
 	}
-	return ""
+	return 0
 }
 
-func (x *Telemetry) GetTotalMemory() uint64 {
+func (x *TelemetryDiskIO) GetWriteBytes() uint64 {
 	if x != nil {
-		return x.TotalMemory
+		return x.WriteBytes
 	}
 	return 0
 }
 
-func (x *Telemetry) GetFreeMemory() uint64 {
+func (x *TelemetryDiskIO) GetReadCount() uint64 {
 	if x != nil {
-		return x.FreeMemory
+		return x.ReadCount
 	}
 	return 0
 }
 
-func (x *Telemetry) GetUsedMemory() uint64 {
+func (x *TelemetryDiskIO) GetWriteCount() uint64 {
 	if x != nil {
-		return x.UsedMemory
+		return x.WriteCount
 	}
 	return 0
 }
 
-func (x *Telemetry) GetTotalCpu() float32 {
+func (x *TelemetryDiskIO) GetIoTime() uint64 {
 	if x != nil {
-		return x.TotalCpu
+		return x.IoTime
 	}
 	return 0
 }
 
-func (x *Telemetry) GetFreeCpu() float32 {
+func (x *TelemetryDiskIO) GetWeightedIoTime() uint64 {
 	if x != nil {
-		return x.FreeCpu
+		return x.WeightedIoTime
 	}
 	return 0
 }
 
-func (x *Telemetry) GetUsedCpu() float32 {
+func (x *TelemetryDiskIO) GetIopsInProgress() uint64 {
 	if x != nil {
-		return x.UsedCpu
+		return x.IopsInProgress
 	}
 	return 0
 }
 
-func (x *Telemetry) GetDisks() []*TelemetryDisk {
+func (x *TelemetryDiskIO) GetReadBytesPerSec() float64 {
 	if x != nil {
-		return x.Disks
+		return x.ReadBytesPerSec
 	}
-	return nil
+	return 0
 }
 
-func (x *Telemetry) GetNetworks() []*TelemetryNetwork {
+func (x *TelemetryDiskIO) GetWriteBytesPerSec() float64 {
 	if x != nil {
-		return x.Networks
+		return x.WriteBytesPerSec
 	}
-	return nil
+	return 0
 }
 
-type SendTelemetryRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Telemetries   []*Telemetry           `protobuf:"bytes,1,rep,name=telemetries,proto3" json:"telemetries,omitempty"`
+func (x *TelemetryDiskIO) GetReadsPerSec() float64 {
+	if x != nil {
+		return x.ReadsPerSec
+	}
+	return 0
+}
+
+func (x *TelemetryDiskIO) GetWritesPerSec() float64 {
+	if x != nil {
+		return x.WritesPerSec
+	}
+	return 0
+}
+
+func (x *TelemetryDiskIO) GetUtilisation() float64 {
+	if x != nil {
+		return x.Utilisation
+	}
+	return 0
+}
+
+func (x *TelemetryDiskIO) GetQueueSize() float64 {
+	if x != nil {
+		return x.QueueSize
+	}
+	return 0
+}
+
+// utilisation between two samples, as percentages
+type TelemetryCPU struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "cpu-total" for the whole host, "cpu0", "cpu1"... per core
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// includes nice
+	User float32 `protobuf:"fixed32,2,opt,name=user,proto3" json:"user,omitempty"`
+	// includes irq and softirq
+	System        float32 `protobuf:"fixed32,3,opt,name=system,proto3" json:"system,omitempty"`
+	Iowait        float32 `protobuf:"fixed32,4,opt,name=iowait,proto3" json:"iowait,omitempty"`
+	Steal         float32 `protobuf:"fixed32,5,opt,name=steal,proto3" json:"steal,omitempty"`
+	Idle          float32 `protobuf:"fixed32,6,opt,name=idle,proto3" json:"idle,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendTelemetryRequest) Reset() {
-	*x = SendTelemetryRequest{}
+func (x *TelemetryCPU) Reset() {
+	*x = TelemetryCPU{}
 	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendTelemetryRequest) String() string {
+func (x *TelemetryCPU) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendTelemetryRequest) ProtoMessage() {}
+func (*TelemetryCPU) ProtoMessage() {}
 
-func (x *SendTelemetryRequest) ProtoReflect() protoreflect.Message {
+func (x *TelemetryCPU) ProtoReflect() protoreflect.Message {
 	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -417,39 +582,84 @@ func (x *SendTelemetryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendTelemetryRequest.ProtoReflect.Descriptor instead.
-func (*SendTelemetryRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use TelemetryCPU.ProtoReflect.Descriptor instead.
+func (*TelemetryCPU) Descriptor() ([]byte, []int) {
 	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *SendTelemetryRequest) GetTelemetries() []*Telemetry {
+func (x *TelemetryCPU) GetName() string {
 	if x != nil {
-		return x.Telemetries
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-type SendTelemetryResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+func (x *TelemetryCPU) GetUser() float32 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *TelemetryCPU) GetSystem() float32 {
+	if x != nil {
+		return x.System
+	}
+	return 0
+}
+
+func (x *TelemetryCPU) GetIowait() float32 {
+	if x != nil {
+		return x.Iowait
+	}
+	return 0
+}
+
+func (x *TelemetryCPU) GetSteal() float32 {
+	if x != nil {
+		return x.Steal
+	}
+	return 0
+}
+
+func (x *TelemetryCPU) GetIdle() float32 {
+	if x != nil {
+		return x.Idle
+	}
+	return 0
+}
+
+// one of the top processes by cpu or by resident memory
+type TelemetryProcess struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pid   int32                  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// truncated sha256 of the command line, arguments often hold secrets
+	CmdlineHash string `protobuf:"bytes,3,opt,name=cmdline_hash,json=cmdlineHash,proto3" json:"cmdline_hash,omitempty"`
+	User        string `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	// since the previous sample, above 100 when using more than one core
+	CpuPercent    float64 `protobuf:"fixed64,5,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"`
+	Rss           uint64  `protobuf:"varint,6,opt,name=rss,proto3" json:"rss,omitempty"`
+	Threads       int32   `protobuf:"varint,7,opt,name=threads,proto3" json:"threads,omitempty"`
+	OpenFds       int32   `protobuf:"varint,8,opt,name=open_fds,json=openFds,proto3" json:"open_fds,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendTelemetryResponse) Reset() {
-	*x = SendTelemetryResponse{}
+func (x *TelemetryProcess) Reset() {
+	*x = TelemetryProcess{}
 	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendTelemetryResponse) String() string {
+func (x *TelemetryProcess) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendTelemetryResponse) ProtoMessage() {}
+func (*TelemetryProcess) ProtoMessage() {}
 
-func (x *SendTelemetryResponse) ProtoReflect() protoreflect.Message {
+func (x *TelemetryProcess) ProtoReflect() protoreflect.Message {
 	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -461,41 +671,96 @@ func (x *SendTelemetryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendTelemetryResponse.ProtoReflect.Descriptor instead.
-func (*SendTelemetryResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use TelemetryProcess.ProtoReflect.Descriptor instead.
+func (*TelemetryProcess) Descriptor() ([]byte, []int) {
 	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *SendTelemetryResponse) GetSuccess() bool {
+func (x *TelemetryProcess) GetPid() int32 {
 	if x != nil {
-		return x.Success
+		return x.Pid
 	}
-	return false
+	return 0
 }
 
-// === HEALTH CHECK ===
-type HealthCheckRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Identifier    string                 `protobuf:"bytes,2,opt,name=identifier,proto3" json:"identifier,omitempty"`
+func (x *TelemetryProcess) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TelemetryProcess) GetCmdlineHash() string {
+	if x != nil {
+		return x.CmdlineHash
+	}
+	return ""
+}
+
+func (x *TelemetryProcess) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *TelemetryProcess) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
+
+func (x *TelemetryProcess) GetRss() uint64 {
+	if x != nil {
+		return x.Rss
+	}
+	return 0
+}
+
+func (x *TelemetryProcess) GetThreads() int32 {
+	if x != nil {
+		return x.Threads
+	}
+	return 0
+}
+
+func (x *TelemetryProcess) GetOpenFds() int32 {
+	if x != nil {
+		return x.OpenFds
+	}
+	return 0
+}
+
+// a hardware sensor reading, thresholds the hardware doesn't expose are zero
+type TelemetrySensor struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "temperature" in celsius or "fan" in rpm
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// hwmon chip name, e.g. "coretemp", or "thermal" for thermal zones
+	Chip          string  `protobuf:"bytes,2,opt,name=chip,proto3" json:"chip,omitempty"`
+	Label         string  `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Value         float64 `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	High          float64 `protobuf:"fixed64,5,opt,name=high,proto3" json:"high,omitempty"`
+	Critical      float64 `protobuf:"fixed64,6,opt,name=critical,proto3" json:"critical,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HealthCheckRequest) Reset() {
-	*x = HealthCheckRequest{}
+func (x *TelemetrySensor) Reset() {
+	*x = TelemetrySensor{}
 	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthCheckRequest) String() string {
+func (x *TelemetrySensor) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthCheckRequest) ProtoMessage() {}
+func (*TelemetrySensor) ProtoMessage() {}
 
-func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
+func (x *TelemetrySensor) ProtoReflect() protoreflect.Message {
 	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -507,47 +772,1868 @@ func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
-func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use TelemetrySensor.ProtoReflect.Descriptor instead.
+func (*TelemetrySensor) Descriptor() ([]byte, []int) {
 	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *HealthCheckRequest) GetTimestamp() *timestamppb.Timestamp {
+func (x *TelemetrySensor) GetKind() string {
 	if x != nil {
-		return x.Timestamp
+		return x.Kind
 	}
-	return nil
+	return ""
 }
 
-func (x *HealthCheckRequest) GetIdentifier() string {
+func (x *TelemetrySensor) GetChip() string {
 	if x != nil {
-		return x.Identifier
+		return x.Chip
 	}
 	return ""
 }
 
-var File_microwatcher_v1_telemetry_service_proto protoreflect.FileDescriptor
+func (x *TelemetrySensor) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
 
-const file_microwatcher_v1_telemetry_service_proto_rawDesc = "" +
-	"\n" +
-	"'microwatcher/v1/telemetry_service.proto\x12\x0fmicrowatcher.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\a\n" +
-	"\x05Empty\"\r\n" +
-	"\vPingRequest\"\x0e\n" +
-	"\fPingResponse\"d\n" +
-	"\x10TelemetryNetwork\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
-	"\n" +
-	"bytes_sent\x18\x02 \x01(\x04R\tbytesSent\x12\x1d\n" +
-	"\n" +
-	"bytes_recv\x18\x03 \x01(\x04R\tbytesRecv\"\x83\x01\n" +
-	"\rTelemetryDisk\x12\x14\n" +
-	"\x05label\x18\x01 \x01(\tR\x05label\x12\x1e\n" +
+func (x *TelemetrySensor) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *TelemetrySensor) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *TelemetrySensor) GetCritical() float64 {
+	if x != nil {
+		return x.Critical
+	}
+	return 0
+}
+
+// a top level cgroup or a container, limits are zero when unlimited
+type TelemetryCgroup struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// relative to the cgroup root, e.g. "/system.slice/docker-<id>.scope"
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// empty for cgroups that aren't containers
+	ContainerId  string `protobuf:"bytes,2,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	CpuUsageUsec uint64 `protobuf:"varint,3,opt,name=cpu_usage_usec,json=cpuUsageUsec,proto3" json:"cpu_usage_usec,omitempty"`
+	// since the previous sample, above 100 when using more than one core
+	CpuPercent         float64 `protobuf:"fixed64,4,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"`
+	ThrottledPeriods   uint64  `protobuf:"varint,5,opt,name=throttled_periods,json=throttledPeriods,proto3" json:"throttled_periods,omitempty"`
+	MemoryCurrent      uint64  `protobuf:"varint,6,opt,name=memory_current,json=memoryCurrent,proto3" json:"memory_current,omitempty"`
+	MemoryMax          uint64  `protobuf:"varint,7,opt,name=memory_max,json=memoryMax,proto3" json:"memory_max,omitempty"`
+	PidsCurrent        uint64  `protobuf:"varint,8,opt,name=pids_current,json=pidsCurrent,proto3" json:"pids_current,omitempty"`
+	PidsMax            uint64  `protobuf:"varint,9,opt,name=pids_max,json=pidsMax,proto3" json:"pids_max,omitempty"`
+	IoReadBytes        uint64  `protobuf:"varint,10,opt,name=io_read_bytes,json=ioReadBytes,proto3" json:"io_read_bytes,omitempty"`
+	IoWriteBytes       uint64  `protobuf:"varint,11,opt,name=io_write_bytes,json=ioWriteBytes,proto3" json:"io_write_bytes,omitempty"`
+	IoReadBytesPerSec  float64 `protobuf:"fixed64,12,opt,name=io_read_bytes_per_sec,json=ioReadBytesPerSec,proto3" json:"io_read_bytes_per_sec,omitempty"`
+	IoWriteBytesPerSec float64 `protobuf:"fixed64,13,opt,name=io_write_bytes_per_sec,json=ioWriteBytesPerSec,proto3" json:"io_write_bytes_per_sec,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TelemetryCgroup) Reset() {
+	*x = TelemetryCgroup{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TelemetryCgroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TelemetryCgroup) ProtoMessage() {}
+
+func (x *TelemetryCgroup) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TelemetryCgroup.ProtoReflect.Descriptor instead.
+func (*TelemetryCgroup) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TelemetryCgroup) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *TelemetryCgroup) GetContainerId() string {
+	if x != nil {
+		return x.ContainerId
+	}
+	return ""
+}
+
+func (x *TelemetryCgroup) GetCpuUsageUsec() uint64 {
+	if x != nil {
+		return x.CpuUsageUsec
+	}
+	return 0
+}
+
+func (x *TelemetryCgroup) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
+
+func (x *TelemetryCgroup) GetThrottledPeriods() uint64 {
+	if x != nil {
+		return x.ThrottledPeriods
+	}
+	return 0
+}
+
+func (x *TelemetryCgroup) GetMemoryCurrent() uint64 {
+	if x != nil {
+		return x.MemoryCurrent
+	}
+	return 0
+}
+
+func (x *TelemetryCgroup) GetMemoryMax() uint64 {
+	if x != nil {
+		return x.MemoryMax
+	}
+	return 0
+}
+
+func (x *TelemetryCgroup) GetPidsCurrent() uint64 {
+	if x != nil {
+		return x.PidsCurrent
+	}
+	return 0
+}
+
+func (x *TelemetryCgroup) GetPidsMax() uint64 {
+	if x != nil {
+		return x.PidsMax
+	}
+	return 0
+}
+
+func (x *TelemetryCgroup) GetIoReadBytes() uint64 {
+	if x != nil {
+		return x.IoReadBytes
+	}
+	return 0
+}
+
+func (x *TelemetryCgroup) GetIoWriteBytes() uint64 {
+	if x != nil {
+		return x.IoWriteBytes
+	}
+	return 0
+}
+
+func (x *TelemetryCgroup) GetIoReadBytesPerSec() float64 {
+	if x != nil {
+		return x.IoReadBytesPerSec
+	}
+	return 0
+}
+
+func (x *TelemetryCgroup) GetIoWriteBytesPerSec() float64 {
+	if x != nil {
+		return x.IoWriteBytesPerSec
+	}
+	return 0
+}
+
+// a systemd unit, as systemctl show reports it
+type TelemetryUnit struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Name      string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	LoadState string                 `protobuf:"bytes,2,opt,name=load_state,json=loadState,proto3" json:"load_state,omitempty"`
+	// e.g. active, failed or activating
+	ActiveState string `protobuf:"bytes,3,opt,name=active_state,json=activeState,proto3" json:"active_state,omitempty"`
+	// e.g. running, exited or dead
+	SubState      string `protobuf:"bytes,4,opt,name=sub_state,json=subState,proto3" json:"sub_state,omitempty"`
+	Restarts      uint32 `protobuf:"varint,5,opt,name=restarts,proto3" json:"restarts,omitempty"`
+	MainPid       int32  `protobuf:"varint,6,opt,name=main_pid,json=mainPid,proto3" json:"main_pid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TelemetryUnit) Reset() {
+	*x = TelemetryUnit{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TelemetryUnit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TelemetryUnit) ProtoMessage() {}
+
+func (x *TelemetryUnit) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TelemetryUnit.ProtoReflect.Descriptor instead.
+func (*TelemetryUnit) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TelemetryUnit) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TelemetryUnit) GetLoadState() string {
+	if x != nil {
+		return x.LoadState
+	}
+	return ""
+}
+
+func (x *TelemetryUnit) GetActiveState() string {
+	if x != nil {
+		return x.ActiveState
+	}
+	return ""
+}
+
+func (x *TelemetryUnit) GetSubState() string {
+	if x != nil {
+		return x.SubState
+	}
+	return ""
+}
+
+func (x *TelemetryUnit) GetRestarts() uint32 {
+	if x != nil {
+		return x.Restarts
+	}
+	return 0
+}
+
+func (x *TelemetryUnit) GetMainPid() int32 {
+	if x != nil {
+		return x.MainPid
+	}
+	return 0
+}
+
+// a certificate read from a PEM file or presented by a TLS endpoint
+type TelemetryCertificate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "file" or "endpoint"
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// the file path or the endpoint host:port
+	Location string `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	// sha256 of the DER encoding, hex encoded
+	Fingerprint string `protobuf:"bytes,3,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	Subject     string `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	Issuer      string `protobuf:"bytes,5,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	// dns names, ip addresses, emails and uris
+	Sans      []string               `protobuf:"bytes,6,rep,name=sans,proto3" json:"sans,omitempty"`
+	NotBefore *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	NotAfter  *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	// whole days left when scanned, negative once expired
+	DaysRemaining int32 `protobuf:"varint,9,opt,name=days_remaining,json=daysRemaining,proto3" json:"days_remaining,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TelemetryCertificate) Reset() {
+	*x = TelemetryCertificate{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TelemetryCertificate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TelemetryCertificate) ProtoMessage() {}
+
+func (x *TelemetryCertificate) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TelemetryCertificate.ProtoReflect.Descriptor instead.
+func (*TelemetryCertificate) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TelemetryCertificate) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *TelemetryCertificate) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *TelemetryCertificate) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *TelemetryCertificate) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *TelemetryCertificate) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *TelemetryCertificate) GetSans() []string {
+	if x != nil {
+		return x.Sans
+	}
+	return nil
+}
+
+func (x *TelemetryCertificate) GetNotBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotBefore
+	}
+	return nil
+}
+
+func (x *TelemetryCertificate) GetNotAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotAfter
+	}
+	return nil
+}
+
+func (x *TelemetryCertificate) GetDaysRemaining() int32 {
+	if x != nil {
+		return x.DaysRemaining
+	}
+	return 0
+}
+
+// a collector that failed or timed out, what it did collect is still sent
+type TelemetryCollectorError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Collector     string                 `protobuf:"bytes,1,opt,name=collector,proto3" json:"collector,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TelemetryCollectorError) Reset() {
+	*x = TelemetryCollectorError{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TelemetryCollectorError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TelemetryCollectorError) ProtoMessage() {}
+
+func (x *TelemetryCollectorError) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TelemetryCollectorError.ProtoReflect.Descriptor instead.
+func (*TelemetryCollectorError) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TelemetryCollectorError) GetCollector() string {
+	if x != nil {
+		return x.Collector
+	}
+	return ""
+}
+
+func (x *TelemetryCollectorError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type TelemetrySystem struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Load1  float64                `protobuf:"fixed64,1,opt,name=load1,proto3" json:"load1,omitempty"`
+	Load5  float64                `protobuf:"fixed64,2,opt,name=load5,proto3" json:"load5,omitempty"`
+	Load15 float64                `protobuf:"fixed64,3,opt,name=load15,proto3" json:"load15,omitempty"`
+	// seconds since boot
+	Uptime        uint64 `protobuf:"varint,4,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	ProcsTotal    uint64 `protobuf:"varint,5,opt,name=procs_total,json=procsTotal,proto3" json:"procs_total,omitempty"`
+	ProcsRunning  uint64 `protobuf:"varint,6,opt,name=procs_running,json=procsRunning,proto3" json:"procs_running,omitempty"`
+	ProcsBlocked  uint64 `protobuf:"varint,7,opt,name=procs_blocked,json=procsBlocked,proto3" json:"procs_blocked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TelemetrySystem) Reset() {
+	*x = TelemetrySystem{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TelemetrySystem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TelemetrySystem) ProtoMessage() {}
+
+func (x *TelemetrySystem) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TelemetrySystem.ProtoReflect.Descriptor instead.
+func (*TelemetrySystem) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TelemetrySystem) GetLoad1() float64 {
+	if x != nil {
+		return x.Load1
+	}
+	return 0
+}
+
+func (x *TelemetrySystem) GetLoad5() float64 {
+	if x != nil {
+		return x.Load5
+	}
+	return 0
+}
+
+func (x *TelemetrySystem) GetLoad15() float64 {
+	if x != nil {
+		return x.Load15
+	}
+	return 0
+}
+
+func (x *TelemetrySystem) GetUptime() uint64 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+func (x *TelemetrySystem) GetProcsTotal() uint64 {
+	if x != nil {
+		return x.ProcsTotal
+	}
+	return 0
+}
+
+func (x *TelemetrySystem) GetProcsRunning() uint64 {
+	if x != nil {
+		return x.ProcsRunning
+	}
+	return 0
+}
+
+func (x *TelemetrySystem) GetProcsBlocked() uint64 {
+	if x != nil {
+		return x.ProcsBlocked
+	}
+	return 0
+}
+
+type Telemetry struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Identifier  string                 `protobuf:"bytes,2,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	TotalMemory uint64                 `protobuf:"varint,3,opt,name=total_memory,json=totalMemory,proto3" json:"total_memory,omitempty"`
+	FreeMemory  uint64                 `protobuf:"varint,4,opt,name=free_memory,json=freeMemory,proto3" json:"free_memory,omitempty"`
+	UsedMemory  uint64                 `protobuf:"varint,5,opt,name=used_memory,json=usedMemory,proto3" json:"used_memory,omitempty"`
+	// cumulative seconds since boot, superseded by cpus
+	TotalCpu float32             `protobuf:"fixed32,6,opt,name=total_cpu,json=totalCpu,proto3" json:"total_cpu,omitempty"`
+	FreeCpu  float32             `protobuf:"fixed32,7,opt,name=free_cpu,json=freeCpu,proto3" json:"free_cpu,omitempty"`
+	UsedCpu  float32             `protobuf:"fixed32,8,opt,name=used_cpu,json=usedCpu,proto3" json:"used_cpu,omitempty"`
+	Disks    []*TelemetryDisk    `protobuf:"bytes,9,rep,name=disks,proto3" json:"disks,omitempty"`
+	Networks []*TelemetryNetwork `protobuf:"bytes,10,rep,name=networks,proto3" json:"networks,omitempty"`
+	Cpus     []*TelemetryCPU     `protobuf:"bytes,11,rep,name=cpus,proto3" json:"cpus,omitempty"`
+	DiskIo   []*TelemetryDiskIO  `protobuf:"bytes,12,rep,name=disk_io,json=diskIo,proto3" json:"disk_io,omitempty"`
+	// available_memory is what can be handed out without swapping, it counts
+	// the reclaimable part of buffers and cached
+	BuffersMemory   uint64 `protobuf:"varint,13,opt,name=buffers_memory,json=buffersMemory,proto3" json:"buffers_memory,omitempty"`
+	CachedMemory    uint64 `protobuf:"varint,14,opt,name=cached_memory,json=cachedMemory,proto3" json:"cached_memory,omitempty"`
+	AvailableMemory uint64 `protobuf:"varint,15,opt,name=available_memory,json=availableMemory,proto3" json:"available_memory,omitempty"`
+	SwapTotal       uint64 `protobuf:"varint,16,opt,name=swap_total,json=swapTotal,proto3" json:"swap_total,omitempty"`
+	SwapUsed        uint64 `protobuf:"varint,17,opt,name=swap_used,json=swapUsed,proto3" json:"swap_used,omitempty"`
+	// unset when the system collector is disabled
+	System          *TelemetrySystem           `protobuf:"bytes,18,opt,name=system,proto3" json:"system,omitempty"`
+	Processes       []*TelemetryProcess        `protobuf:"bytes,19,rep,name=processes,proto3" json:"processes,omitempty"`
+	Sensors         []*TelemetrySensor         `protobuf:"bytes,20,rep,name=sensors,proto3" json:"sensors,omitempty"`
+	Cgroups         []*TelemetryCgroup         `protobuf:"bytes,21,rep,name=cgroups,proto3" json:"cgroups,omitempty"`
+	CollectorErrors []*TelemetryCollectorError `protobuf:"bytes,22,rep,name=collector_errors,json=collectorErrors,proto3" json:"collector_errors,omitempty"`
+	Units           []*TelemetryUnit           `protobuf:"bytes,23,rep,name=units,proto3" json:"units,omitempty"`
+	Certificates    []*TelemetryCertificate    `protobuf:"bytes,24,rep,name=certificates,proto3" json:"certificates,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Telemetry) Reset() {
+	*x = Telemetry{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Telemetry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Telemetry) ProtoMessage() {}
+
+func (x *Telemetry) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Telemetry.ProtoReflect.Descriptor instead.
+func (*Telemetry) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Telemetry) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Telemetry) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *Telemetry) GetTotalMemory() uint64 {
+	if x != nil {
+		return x.TotalMemory
+	}
+	return 0
+}
+
+func (x *Telemetry) GetFreeMemory() uint64 {
+	if x != nil {
+		return x.FreeMemory
+	}
+	return 0
+}
+
+func (x *Telemetry) GetUsedMemory() uint64 {
+	if x != nil {
+		return x.UsedMemory
+	}
+	return 0
+}
+
+func (x *Telemetry) GetTotalCpu() float32 {
+	if x != nil {
+		return x.TotalCpu
+	}
+	return 0
+}
+
+func (x *Telemetry) GetFreeCpu() float32 {
+	if x != nil {
+		return x.FreeCpu
+	}
+	return 0
+}
+
+func (x *Telemetry) GetUsedCpu() float32 {
+	if x != nil {
+		return x.UsedCpu
+	}
+	return 0
+}
+
+func (x *Telemetry) GetDisks() []*TelemetryDisk {
+	if x != nil {
+		return x.Disks
+	}
+	return nil
+}
+
+func (x *Telemetry) GetNetworks() []*TelemetryNetwork {
+	if x != nil {
+		return x.Networks
+	}
+	return nil
+}
+
+func (x *Telemetry) GetCpus() []*TelemetryCPU {
+	if x != nil {
+		return x.Cpus
+	}
+	return nil
+}
+
+func (x *Telemetry) GetDiskIo() []*TelemetryDiskIO {
+	if x != nil {
+		return x.DiskIo
+	}
+	return nil
+}
+
+func (x *Telemetry) GetBuffersMemory() uint64 {
+	if x != nil {
+		return x.BuffersMemory
+	}
+	return 0
+}
+
+func (x *Telemetry) GetCachedMemory() uint64 {
+	if x != nil {
+		return x.CachedMemory
+	}
+	return 0
+}
+
+func (x *Telemetry) GetAvailableMemory() uint64 {
+	if x != nil {
+		return x.AvailableMemory
+	}
+	return 0
+}
+
+func (x *Telemetry) GetSwapTotal() uint64 {
+	if x != nil {
+		return x.SwapTotal
+	}
+	return 0
+}
+
+func (x *Telemetry) GetSwapUsed() uint64 {
+	if x != nil {
+		return x.SwapUsed
+	}
+	return 0
+}
+
+func (x *Telemetry) GetSystem() *TelemetrySystem {
+	if x != nil {
+		return x.System
+	}
+	return nil
+}
+
+func (x *Telemetry) GetProcesses() []*TelemetryProcess {
+	if x != nil {
+		return x.Processes
+	}
+	return nil
+}
+
+func (x *Telemetry) GetSensors() []*TelemetrySensor {
+	if x != nil {
+		return x.Sensors
+	}
+	return nil
+}
+
+func (x *Telemetry) GetCgroups() []*TelemetryCgroup {
+	if x != nil {
+		return x.Cgroups
+	}
+	return nil
+}
+
+func (x *Telemetry) GetCollectorErrors() []*TelemetryCollectorError {
+	if x != nil {
+		return x.CollectorErrors
+	}
+	return nil
+}
+
+func (x *Telemetry) GetUnits() []*TelemetryUnit {
+	if x != nil {
+		return x.Units
+	}
+	return nil
+}
+
+func (x *Telemetry) GetCertificates() []*TelemetryCertificate {
+	if x != nil {
+		return x.Certificates
+	}
+	return nil
+}
+
+type SendTelemetryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Telemetries   []*Telemetry           `protobuf:"bytes,1,rep,name=telemetries,proto3" json:"telemetries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendTelemetryRequest) Reset() {
+	*x = SendTelemetryRequest{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendTelemetryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendTelemetryRequest) ProtoMessage() {}
+
+func (x *SendTelemetryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendTelemetryRequest.ProtoReflect.Descriptor instead.
+func (*SendTelemetryRequest) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SendTelemetryRequest) GetTelemetries() []*Telemetry {
+	if x != nil {
+		return x.Telemetries
+	}
+	return nil
+}
+
+type SendTelemetryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendTelemetryResponse) Reset() {
+	*x = SendTelemetryResponse{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendTelemetryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendTelemetryResponse) ProtoMessage() {}
+
+func (x *SendTelemetryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendTelemetryResponse.ProtoReflect.Descriptor instead.
+func (*SendTelemetryResponse) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SendTelemetryResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// === METRICS ===
+// an application specific sample, e.g. from an exec collector
+type CustomMetric struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// what produced the metric, e.g. the exec collector name
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Name   string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// "gauge", "counter" or "untyped"
+	Type          string            `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Value         float64           `protobuf:"fixed64,5,opt,name=value,proto3" json:"value,omitempty"`
+	Labels        map[string]string `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomMetric) Reset() {
+	*x = CustomMetric{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomMetric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomMetric) ProtoMessage() {}
+
+func (x *CustomMetric) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomMetric.ProtoReflect.Descriptor instead.
+func (*CustomMetric) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CustomMetric) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *CustomMetric) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *CustomMetric) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CustomMetric) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CustomMetric) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *CustomMetric) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type SendMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Metrics       []*CustomMetric        `protobuf:"bytes,2,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendMetricsRequest) Reset() {
+	*x = SendMetricsRequest{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMetricsRequest) ProtoMessage() {}
+
+func (x *SendMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMetricsRequest.ProtoReflect.Descriptor instead.
+func (*SendMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SendMetricsRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *SendMetricsRequest) GetMetrics() []*CustomMetric {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+type SendMetricsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendMetricsResponse) Reset() {
+	*x = SendMetricsResponse{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMetricsResponse) ProtoMessage() {}
+
+func (x *SendMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMetricsResponse.ProtoReflect.Descriptor instead.
+func (*SendMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SendMetricsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// === LOGS ===
+type LogEntry struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// the log collector name
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Path   string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	// debug, info, warn, error or fatal when recognised, empty when unknown
+	Severity string `protobuf:"bytes,4,opt,name=severity,proto3" json:"severity,omitempty"`
+	Message  string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	// extracted by the json or regex parsers
+	Fields        map[string]string `protobuf:"bytes,6,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *LogEntry) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *LogEntry) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *LogEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *LogEntry) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *LogEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LogEntry) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type SendLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Entries       []*LogEntry            `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendLogsRequest) Reset() {
+	*x = SendLogsRequest{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendLogsRequest) ProtoMessage() {}
+
+func (x *SendLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendLogsRequest.ProtoReflect.Descriptor instead.
+func (*SendLogsRequest) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SendLogsRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *SendLogsRequest) GetEntries() []*LogEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type SendLogsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendLogsResponse) Reset() {
+	*x = SendLogsResponse{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendLogsResponse) ProtoMessage() {}
+
+func (x *SendLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendLogsResponse.ProtoReflect.Descriptor instead.
+func (*SendLogsResponse) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SendLogsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// === PROBES ===
+type ProbeResult struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// the probe name
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// http, tcp or dns
+	Kind      string  `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Target    string  `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	Success   bool    `protobuf:"varint,5,opt,name=success,proto3" json:"success,omitempty"`
+	LatencyMs float64 `protobuf:"fixed64,6,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	// zero unless an http probe got a response
+	StatusCode int32 `protobuf:"varint,7,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	// unset unless the probe went over TLS
+	CertNotAfter *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=cert_not_after,json=certNotAfter,proto3" json:"cert_not_after,omitempty"`
+	// why the probe failed, empty on success
+	Error         string `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProbeResult) Reset() {
+	*x = ProbeResult{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProbeResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeResult) ProtoMessage() {}
+
+func (x *ProbeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeResult.ProtoReflect.Descriptor instead.
+func (*ProbeResult) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ProbeResult) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ProbeResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProbeResult) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ProbeResult) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *ProbeResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ProbeResult) GetLatencyMs() float64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *ProbeResult) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *ProbeResult) GetCertNotAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CertNotAfter
+	}
+	return nil
+}
+
+func (x *ProbeResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SendProbeResultsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Results       []*ProbeResult         `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendProbeResultsRequest) Reset() {
+	*x = SendProbeResultsRequest{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendProbeResultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendProbeResultsRequest) ProtoMessage() {}
+
+func (x *SendProbeResultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendProbeResultsRequest.ProtoReflect.Descriptor instead.
+func (*SendProbeResultsRequest) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SendProbeResultsRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *SendProbeResultsRequest) GetResults() []*ProbeResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type SendProbeResultsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendProbeResultsResponse) Reset() {
+	*x = SendProbeResultsResponse{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendProbeResultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendProbeResultsResponse) ProtoMessage() {}
+
+func (x *SendProbeResultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendProbeResultsResponse.ProtoReflect.Descriptor instead.
+func (*SendProbeResultsResponse) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SendProbeResultsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// === HEALTH CHECK ===
+// a network interface that is up with a routable address
+type InventoryInterface struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Mac           string                 `protobuf:"bytes,2,opt,name=mac,proto3" json:"mac,omitempty"`
+	Addresses     []string               `protobuf:"bytes,3,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventoryInterface) Reset() {
+	*x = InventoryInterface{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryInterface) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryInterface) ProtoMessage() {}
+
+func (x *InventoryInterface) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryInterface.ProtoReflect.Descriptor instead.
+func (*InventoryInterface) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *InventoryInterface) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *InventoryInterface) GetMac() string {
+	if x != nil {
+		return x.Mac
+	}
+	return ""
+}
+
+func (x *InventoryInterface) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+// facts about the host that only change on upgrades, reboots or network
+// changes
+type DeviceInventory struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Hostname string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	// e.g. linux
+	Os string `protobuf:"bytes,2,opt,name=os,proto3" json:"os,omitempty"`
+	// the distribution, e.g. ubuntu
+	Platform        string                 `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`
+	PlatformFamily  string                 `protobuf:"bytes,4,opt,name=platform_family,json=platformFamily,proto3" json:"platform_family,omitempty"`
+	PlatformVersion string                 `protobuf:"bytes,5,opt,name=platform_version,json=platformVersion,proto3" json:"platform_version,omitempty"`
+	KernelVersion   string                 `protobuf:"bytes,6,opt,name=kernel_version,json=kernelVersion,proto3" json:"kernel_version,omitempty"`
+	Arch            string                 `protobuf:"bytes,7,opt,name=arch,proto3" json:"arch,omitempty"`
+	CpuModel        string                 `protobuf:"bytes,8,opt,name=cpu_model,json=cpuModel,proto3" json:"cpu_model,omitempty"`
+	CpuCores        int32                  `protobuf:"varint,9,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	CpuThreads      int32                  `protobuf:"varint,10,opt,name=cpu_threads,json=cpuThreads,proto3" json:"cpu_threads,omitempty"`
+	TotalMemory     uint64                 `protobuf:"varint,11,opt,name=total_memory,json=totalMemory,proto3" json:"total_memory,omitempty"`
+	BootTime        *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=boot_time,json=bootTime,proto3" json:"boot_time,omitempty"`
+	Interfaces      []*InventoryInterface  `protobuf:"bytes,13,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	AgentVersion    string                 `protobuf:"bytes,14,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
+	// e.g. kvm or docker, empty on bare metal
+	VirtualizationSystem string `protobuf:"bytes,15,opt,name=virtualization_system,json=virtualizationSystem,proto3" json:"virtualization_system,omitempty"`
+	// guest or host
+	VirtualizationRole string `protobuf:"bytes,16,opt,name=virtualization_role,json=virtualizationRole,proto3" json:"virtualization_role,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *DeviceInventory) Reset() {
+	*x = DeviceInventory{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceInventory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceInventory) ProtoMessage() {}
+
+func (x *DeviceInventory) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceInventory.ProtoReflect.Descriptor instead.
+func (*DeviceInventory) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DeviceInventory) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *DeviceInventory) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *DeviceInventory) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *DeviceInventory) GetPlatformFamily() string {
+	if x != nil {
+		return x.PlatformFamily
+	}
+	return ""
+}
+
+func (x *DeviceInventory) GetPlatformVersion() string {
+	if x != nil {
+		return x.PlatformVersion
+	}
+	return ""
+}
+
+func (x *DeviceInventory) GetKernelVersion() string {
+	if x != nil {
+		return x.KernelVersion
+	}
+	return ""
+}
+
+func (x *DeviceInventory) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *DeviceInventory) GetCpuModel() string {
+	if x != nil {
+		return x.CpuModel
+	}
+	return ""
+}
+
+func (x *DeviceInventory) GetCpuCores() int32 {
+	if x != nil {
+		return x.CpuCores
+	}
+	return 0
+}
+
+func (x *DeviceInventory) GetCpuThreads() int32 {
+	if x != nil {
+		return x.CpuThreads
+	}
+	return 0
+}
+
+func (x *DeviceInventory) GetTotalMemory() uint64 {
+	if x != nil {
+		return x.TotalMemory
+	}
+	return 0
+}
+
+func (x *DeviceInventory) GetBootTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BootTime
+	}
+	return nil
+}
+
+func (x *DeviceInventory) GetInterfaces() []*InventoryInterface {
+	if x != nil {
+		return x.Interfaces
+	}
+	return nil
+}
+
+func (x *DeviceInventory) GetAgentVersion() string {
+	if x != nil {
+		return x.AgentVersion
+	}
+	return ""
+}
+
+func (x *DeviceInventory) GetVirtualizationSystem() string {
+	if x != nil {
+		return x.VirtualizationSystem
+	}
+	return ""
+}
+
+func (x *DeviceInventory) GetVirtualizationRole() string {
+	if x != nil {
+		return x.VirtualizationRole
+	}
+	return ""
+}
+
+type HealthCheckRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp  *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Identifier string                 `protobuf:"bytes,2,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	// only set on the first health check and when the inventory changed
+	Inventory     *DeviceInventory `protobuf:"bytes,3,opt,name=inventory,proto3" json:"inventory,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckRequest) Reset() {
+	*x = HealthCheckRequest{}
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckRequest) ProtoMessage() {}
+
+func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v1_telemetry_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
+func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v1_telemetry_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *HealthCheckRequest) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *HealthCheckRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *HealthCheckRequest) GetInventory() *DeviceInventory {
+	if x != nil {
+		return x.Inventory
+	}
+	return nil
+}
+
+var File_microwatcher_v1_telemetry_service_proto protoreflect.FileDescriptor
+
+const file_microwatcher_v1_telemetry_service_proto_rawDesc = "" +
+	"\n" +
+	"'microwatcher/v1/telemetry_service.proto\x12\x0fmicrowatcher.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\a\n" +
+	"\x05Empty\"\r\n" +
+	"\vPingRequest\"\x0e\n" +
+	"\fPingResponse\"\xda\x03\n" +
+	"\x10TelemetryNetwork\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"bytes_sent\x18\x02 \x01(\x04R\tbytesSent\x12\x1d\n" +
+	"\n" +
+	"bytes_recv\x18\x03 \x01(\x04R\tbytesRecv\x12!\n" +
+	"\fpackets_sent\x18\x04 \x01(\x04R\vpacketsSent\x12!\n" +
+	"\fpackets_recv\x18\x05 \x01(\x04R\vpacketsRecv\x12\x1b\n" +
+	"\terrors_in\x18\x06 \x01(\x04R\berrorsIn\x12\x1d\n" +
+	"\n" +
+	"errors_out\x18\a \x01(\x04R\terrorsOut\x12\x19\n" +
+	"\bdrops_in\x18\b \x01(\x04R\adropsIn\x12\x1b\n" +
+	"\tdrops_out\x18\t \x01(\x04R\bdropsOut\x12+\n" +
+	"\x12bytes_sent_per_sec\x18\n" +
+	" \x01(\x01R\x0fbytesSentPerSec\x12+\n" +
+	"\x12bytes_recv_per_sec\x18\v \x01(\x01R\x0fbytesRecvPerSec\x12/\n" +
+	"\x14packets_sent_per_sec\x18\f \x01(\x01R\x11packetsSentPerSec\x12/\n" +
+	"\x14packets_recv_per_sec\x18\r \x01(\x01R\x11packetsRecvPerSec\"\x80\x02\n" +
+	"\rTelemetryDisk\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12\x1e\n" +
 	"\n" +
 	"mountpoint\x18\x02 \x01(\tR\n" +
 	"mountpoint\x12\x14\n" +
 	"\x05total\x18\x03 \x01(\x04R\x05total\x12\x12\n" +
 	"\x04free\x18\x04 \x01(\x04R\x04free\x12\x12\n" +
-	"\x04used\x18\x05 \x01(\x04R\x04used\"\x92\x03\n" +
+	"\x04used\x18\x05 \x01(\x04R\x04used\x12\x16\n" +
+	"\x06fstype\x18\x06 \x01(\tR\x06fstype\x12!\n" +
+	"\finodes_total\x18\a \x01(\x04R\vinodesTotal\x12\x1f\n" +
+	"\vinodes_used\x18\b \x01(\x04R\n" +
+	"inodesUsed\x12\x1f\n" +
+	"\vinodes_free\x18\t \x01(\x04R\n" +
+	"inodesFree\"\xf9\x03\n" +
+	"\x0fTelemetryDiskIO\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"read_bytes\x18\x02 \x01(\x04R\treadBytes\x12\x1f\n" +
+	"\vwrite_bytes\x18\x03 \x01(\x04R\n" +
+	"writeBytes\x12\x1d\n" +
+	"\n" +
+	"read_count\x18\x04 \x01(\x04R\treadCount\x12\x1f\n" +
+	"\vwrite_count\x18\x05 \x01(\x04R\n" +
+	"writeCount\x12\x17\n" +
+	"\aio_time\x18\x06 \x01(\x04R\x06ioTime\x12(\n" +
+	"\x10weighted_io_time\x18\a \x01(\x04R\x0eweightedIoTime\x12(\n" +
+	"\x10iops_in_progress\x18\b \x01(\x04R\x0eiopsInProgress\x12+\n" +
+	"\x12read_bytes_per_sec\x18\t \x01(\x01R\x0freadBytesPerSec\x12-\n" +
+	"\x13write_bytes_per_sec\x18\n" +
+	" \x01(\x01R\x10writeBytesPerSec\x12\"\n" +
+	"\rreads_per_sec\x18\v \x01(\x01R\vreadsPerSec\x12$\n" +
+	"\x0ewrites_per_sec\x18\f \x01(\x01R\fwritesPerSec\x12 \n" +
+	"\vutilisation\x18\r \x01(\x01R\vutilisation\x12\x1d\n" +
+	"\n" +
+	"queue_size\x18\x0e \x01(\x01R\tqueueSize\"\x90\x01\n" +
+	"\fTelemetryCPU\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\x02R\x04user\x12\x16\n" +
+	"\x06system\x18\x03 \x01(\x02R\x06system\x12\x16\n" +
+	"\x06iowait\x18\x04 \x01(\x02R\x06iowait\x12\x14\n" +
+	"\x05steal\x18\x05 \x01(\x02R\x05steal\x12\x12\n" +
+	"\x04idle\x18\x06 \x01(\x02R\x04idle\"\xd7\x01\n" +
+	"\x10TelemetryProcess\x12\x10\n" +
+	"\x03pid\x18\x01 \x01(\x05R\x03pid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12!\n" +
+	"\fcmdline_hash\x18\x03 \x01(\tR\vcmdlineHash\x12\x12\n" +
+	"\x04user\x18\x04 \x01(\tR\x04user\x12\x1f\n" +
+	"\vcpu_percent\x18\x05 \x01(\x01R\n" +
+	"cpuPercent\x12\x10\n" +
+	"\x03rss\x18\x06 \x01(\x04R\x03rss\x12\x18\n" +
+	"\athreads\x18\a \x01(\x05R\athreads\x12\x19\n" +
+	"\bopen_fds\x18\b \x01(\x05R\aopenFds\"\x95\x01\n" +
+	"\x0fTelemetrySensor\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04chip\x18\x02 \x01(\tR\x04chip\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x01R\x05value\x12\x12\n" +
+	"\x04high\x18\x05 \x01(\x01R\x04high\x12\x1a\n" +
+	"\bcritical\x18\x06 \x01(\x01R\bcritical\"\xf0\x03\n" +
+	"\x0fTelemetryCgroup\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12!\n" +
+	"\fcontainer_id\x18\x02 \x01(\tR\vcontainerId\x12$\n" +
+	"\x0ecpu_usage_usec\x18\x03 \x01(\x04R\fcpuUsageUsec\x12\x1f\n" +
+	"\vcpu_percent\x18\x04 \x01(\x01R\n" +
+	"cpuPercent\x12+\n" +
+	"\x11throttled_periods\x18\x05 \x01(\x04R\x10throttledPeriods\x12%\n" +
+	"\x0ememory_current\x18\x06 \x01(\x04R\rmemoryCurrent\x12\x1d\n" +
+	"\n" +
+	"memory_max\x18\a \x01(\x04R\tmemoryMax\x12!\n" +
+	"\fpids_current\x18\b \x01(\x04R\vpidsCurrent\x12\x19\n" +
+	"\bpids_max\x18\t \x01(\x04R\apidsMax\x12\"\n" +
+	"\rio_read_bytes\x18\n" +
+	" \x01(\x04R\vioReadBytes\x12$\n" +
+	"\x0eio_write_bytes\x18\v \x01(\x04R\fioWriteBytes\x120\n" +
+	"\x15io_read_bytes_per_sec\x18\f \x01(\x01R\x11ioReadBytesPerSec\x122\n" +
+	"\x16io_write_bytes_per_sec\x18\r \x01(\x01R\x12ioWriteBytesPerSec\"\xb9\x01\n" +
+	"\rTelemetryUnit\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"load_state\x18\x02 \x01(\tR\tloadState\x12!\n" +
+	"\factive_state\x18\x03 \x01(\tR\vactiveState\x12\x1b\n" +
+	"\tsub_state\x18\x04 \x01(\tR\bsubState\x12\x1a\n" +
+	"\brestarts\x18\x05 \x01(\rR\brestarts\x12\x19\n" +
+	"\bmain_pid\x18\x06 \x01(\x05R\amainPid\"\xcd\x02\n" +
+	"\x14TelemetryCertificate\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x1a\n" +
+	"\blocation\x18\x02 \x01(\tR\blocation\x12 \n" +
+	"\vfingerprint\x18\x03 \x01(\tR\vfingerprint\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12\x16\n" +
+	"\x06issuer\x18\x05 \x01(\tR\x06issuer\x12\x12\n" +
+	"\x04sans\x18\x06 \x03(\tR\x04sans\x129\n" +
+	"\n" +
+	"not_before\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tnotBefore\x127\n" +
+	"\tnot_after\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\bnotAfter\x12%\n" +
+	"\x0edays_remaining\x18\t \x01(\x05R\rdaysRemaining\"M\n" +
+	"\x17TelemetryCollectorError\x12\x1c\n" +
+	"\tcollector\x18\x01 \x01(\tR\tcollector\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\xd8\x01\n" +
+	"\x0fTelemetrySystem\x12\x14\n" +
+	"\x05load1\x18\x01 \x01(\x01R\x05load1\x12\x14\n" +
+	"\x05load5\x18\x02 \x01(\x01R\x05load5\x12\x16\n" +
+	"\x06load15\x18\x03 \x01(\x01R\x06load15\x12\x16\n" +
+	"\x06uptime\x18\x04 \x01(\x04R\x06uptime\x12\x1f\n" +
+	"\vprocs_total\x18\x05 \x01(\x04R\n" +
+	"procsTotal\x12#\n" +
+	"\rprocs_running\x18\x06 \x01(\x04R\fprocsRunning\x12#\n" +
+	"\rprocs_blocked\x18\a \x01(\x04R\fprocsBlocked\"\xfc\b\n" +
 	"\tTelemetry\x128\n" +
 	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1e\n" +
 	"\n" +
@@ -563,18 +2649,115 @@ const file_microwatcher_v1_telemetry_service_proto_rawDesc = "" +
 	"\bused_cpu\x18\b \x01(\x02R\ausedCpu\x124\n" +
 	"\x05disks\x18\t \x03(\v2\x1e.microwatcher.v1.TelemetryDiskR\x05disks\x12=\n" +
 	"\bnetworks\x18\n" +
-	" \x03(\v2!.microwatcher.v1.TelemetryNetworkR\bnetworks\"T\n" +
+	" \x03(\v2!.microwatcher.v1.TelemetryNetworkR\bnetworks\x121\n" +
+	"\x04cpus\x18\v \x03(\v2\x1d.microwatcher.v1.TelemetryCPUR\x04cpus\x129\n" +
+	"\adisk_io\x18\f \x03(\v2 .microwatcher.v1.TelemetryDiskIOR\x06diskIo\x12%\n" +
+	"\x0ebuffers_memory\x18\r \x01(\x04R\rbuffersMemory\x12#\n" +
+	"\rcached_memory\x18\x0e \x01(\x04R\fcachedMemory\x12)\n" +
+	"\x10available_memory\x18\x0f \x01(\x04R\x0favailableMemory\x12\x1d\n" +
+	"\n" +
+	"swap_total\x18\x10 \x01(\x04R\tswapTotal\x12\x1b\n" +
+	"\tswap_used\x18\x11 \x01(\x04R\bswapUsed\x128\n" +
+	"\x06system\x18\x12 \x01(\v2 .microwatcher.v1.TelemetrySystemR\x06system\x12?\n" +
+	"\tprocesses\x18\x13 \x03(\v2!.microwatcher.v1.TelemetryProcessR\tprocesses\x12:\n" +
+	"\asensors\x18\x14 \x03(\v2 .microwatcher.v1.TelemetrySensorR\asensors\x12:\n" +
+	"\acgroups\x18\x15 \x03(\v2 .microwatcher.v1.TelemetryCgroupR\acgroups\x12S\n" +
+	"\x10collector_errors\x18\x16 \x03(\v2(.microwatcher.v1.TelemetryCollectorErrorR\x0fcollectorErrors\x124\n" +
+	"\x05units\x18\x17 \x03(\v2\x1e.microwatcher.v1.TelemetryUnitR\x05units\x12I\n" +
+	"\fcertificates\x18\x18 \x03(\v2%.microwatcher.v1.TelemetryCertificateR\fcertificates\"T\n" +
 	"\x14SendTelemetryRequest\x12<\n" +
 	"\vtelemetries\x18\x01 \x03(\v2\x1a.microwatcher.v1.TelemetryR\vtelemetries\"1\n" +
 	"\x15SendTelemetryResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"n\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x9c\x02\n" +
+	"\fCustomMetric\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x14\n" +
+	"\x05value\x18\x05 \x01(\x01R\x05value\x12A\n" +
+	"\x06labels\x18\x06 \x03(\v2).microwatcher.v1.CustomMetric.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"m\n" +
+	"\x12SendMetricsRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x127\n" +
+	"\ametrics\x18\x02 \x03(\v2\x1d.microwatcher.v1.CustomMetricR\ametrics\"/\n" +
+	"\x13SendMetricsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xa0\x02\n" +
+	"\bLogEntry\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x1a\n" +
+	"\bseverity\x18\x04 \x01(\tR\bseverity\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12=\n" +
+	"\x06fields\x18\x06 \x03(\v2%.microwatcher.v1.LogEntry.FieldsEntryR\x06fields\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"f\n" +
+	"\x0fSendLogsRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x123\n" +
+	"\aentries\x18\x02 \x03(\v2\x19.microwatcher.v1.LogEntryR\aentries\",\n" +
+	"\x10SendLogsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xb9\x02\n" +
+	"\vProbeResult\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06target\x18\x04 \x01(\tR\x06target\x12\x18\n" +
+	"\asuccess\x18\x05 \x01(\bR\asuccess\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x06 \x01(\x01R\tlatencyMs\x12\x1f\n" +
+	"\vstatus_code\x18\a \x01(\x05R\n" +
+	"statusCode\x12@\n" +
+	"\x0ecert_not_after\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\fcertNotAfter\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\"q\n" +
+	"\x17SendProbeResultsRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x126\n" +
+	"\aresults\x18\x02 \x03(\v2\x1c.microwatcher.v1.ProbeResultR\aresults\"4\n" +
+	"\x18SendProbeResultsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"X\n" +
+	"\x12InventoryInterface\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03mac\x18\x02 \x01(\tR\x03mac\x12\x1c\n" +
+	"\taddresses\x18\x03 \x03(\tR\taddresses\"\xef\x04\n" +
+	"\x0fDeviceInventory\x12\x1a\n" +
+	"\bhostname\x18\x01 \x01(\tR\bhostname\x12\x0e\n" +
+	"\x02os\x18\x02 \x01(\tR\x02os\x12\x1a\n" +
+	"\bplatform\x18\x03 \x01(\tR\bplatform\x12'\n" +
+	"\x0fplatform_family\x18\x04 \x01(\tR\x0eplatformFamily\x12)\n" +
+	"\x10platform_version\x18\x05 \x01(\tR\x0fplatformVersion\x12%\n" +
+	"\x0ekernel_version\x18\x06 \x01(\tR\rkernelVersion\x12\x12\n" +
+	"\x04arch\x18\a \x01(\tR\x04arch\x12\x1b\n" +
+	"\tcpu_model\x18\b \x01(\tR\bcpuModel\x12\x1b\n" +
+	"\tcpu_cores\x18\t \x01(\x05R\bcpuCores\x12\x1f\n" +
+	"\vcpu_threads\x18\n" +
+	" \x01(\x05R\n" +
+	"cpuThreads\x12!\n" +
+	"\ftotal_memory\x18\v \x01(\x04R\vtotalMemory\x127\n" +
+	"\tboot_time\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\bbootTime\x12C\n" +
+	"\n" +
+	"interfaces\x18\r \x03(\v2#.microwatcher.v1.InventoryInterfaceR\n" +
+	"interfaces\x12#\n" +
+	"\ragent_version\x18\x0e \x01(\tR\fagentVersion\x123\n" +
+	"\x15virtualization_system\x18\x0f \x01(\tR\x14virtualizationSystem\x12/\n" +
+	"\x13virtualization_role\x18\x10 \x01(\tR\x12virtualizationRole\"\xae\x01\n" +
 	"\x12HealthCheckRequest\x128\n" +
 	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1e\n" +
 	"\n" +
 	"identifier\x18\x02 \x01(\tR\n" +
-	"identifier2\x89\x02\n" +
+	"identifier\x12>\n" +
+	"\tinventory\x18\x03 \x01(\v2 .microwatcher.v1.DeviceInventoryR\tinventory2\xa3\x04\n" +
 	"\x10TelemetryService\x12`\n" +
-	"\rSendTelemetry\x12%.microwatcher.v1.SendTelemetryRequest\x1a&.microwatcher.v1.SendTelemetryResponse\"\x00\x12L\n" +
+	"\rSendTelemetry\x12%.microwatcher.v1.SendTelemetryRequest\x1a&.microwatcher.v1.SendTelemetryResponse\"\x00\x12Z\n" +
+	"\vSendMetrics\x12#.microwatcher.v1.SendMetricsRequest\x1a$.microwatcher.v1.SendMetricsResponse\"\x00\x12Q\n" +
+	"\bSendLogs\x12 .microwatcher.v1.SendLogsRequest\x1a!.microwatcher.v1.SendLogsResponse\"\x00\x12i\n" +
+	"\x10SendProbeResults\x12(.microwatcher.v1.SendProbeResultsRequest\x1a).microwatcher.v1.SendProbeResultsResponse\"\x00\x12L\n" +
 	"\vHealthCheck\x12#.microwatcher.v1.HealthCheckRequest\x1a\x16.microwatcher.v1.Empty\"\x00\x12E\n" +
 	"\x04Ping\x12\x1c.microwatcher.v1.PingRequest\x1a\x1d.microwatcher.v1.PingResponse\"\x00BCZAgithub.com/microwatcher/shared/gen/microwatcher/v1;microwatcherv1b\x06proto3"
 
@@ -590,36 +2773,87 @@ func file_microwatcher_v1_telemetry_service_proto_rawDescGZIP() []byte {
 	return file_microwatcher_v1_telemetry_service_proto_rawDescData
 }
 
-var file_microwatcher_v1_telemetry_service_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_microwatcher_v1_telemetry_service_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_microwatcher_v1_telemetry_service_proto_goTypes = []any{
-	(*Empty)(nil),                 // 0: microwatcher.v1.Empty
-	(*PingRequest)(nil),           // 1: microwatcher.v1.PingRequest
-	(*PingResponse)(nil),          // 2: microwatcher.v1.PingResponse
-	(*TelemetryNetwork)(nil),      // 3: microwatcher.v1.TelemetryNetwork
-	(*TelemetryDisk)(nil),         // 4: microwatcher.v1.TelemetryDisk
-	(*Telemetry)(nil),             // 5: microwatcher.v1.Telemetry
-	(*SendTelemetryRequest)(nil),  // 6: microwatcher.v1.SendTelemetryRequest
-	(*SendTelemetryResponse)(nil), // 7: microwatcher.v1.SendTelemetryResponse
-	(*HealthCheckRequest)(nil),    // 8: microwatcher.v1.HealthCheckRequest
-	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*Empty)(nil),                    // 0: microwatcher.v1.Empty
+	(*PingRequest)(nil),              // 1: microwatcher.v1.PingRequest
+	(*PingResponse)(nil),             // 2: microwatcher.v1.PingResponse
+	(*TelemetryNetwork)(nil),         // 3: microwatcher.v1.TelemetryNetwork
+	(*TelemetryDisk)(nil),            // 4: microwatcher.v1.TelemetryDisk
+	(*TelemetryDiskIO)(nil),          // 5: microwatcher.v1.TelemetryDiskIO
+	(*TelemetryCPU)(nil),             // 6: microwatcher.v1.TelemetryCPU
+	(*TelemetryProcess)(nil),         // 7: microwatcher.v1.TelemetryProcess
+	(*TelemetrySensor)(nil),          // 8: microwatcher.v1.TelemetrySensor
+	(*TelemetryCgroup)(nil),          // 9: microwatcher.v1.TelemetryCgroup
+	(*TelemetryUnit)(nil),            // 10: microwatcher.v1.TelemetryUnit
+	(*TelemetryCertificate)(nil),     // 11: microwatcher.v1.TelemetryCertificate
+	(*TelemetryCollectorError)(nil),  // 12: microwatcher.v1.TelemetryCollectorError
+	(*TelemetrySystem)(nil),          // 13: microwatcher.v1.TelemetrySystem
+	(*Telemetry)(nil),                // 14: microwatcher.v1.Telemetry
+	(*SendTelemetryRequest)(nil),     // 15: microwatcher.v1.SendTelemetryRequest
+	(*SendTelemetryResponse)(nil),    // 16: microwatcher.v1.SendTelemetryResponse
+	(*CustomMetric)(nil),             // 17: microwatcher.v1.CustomMetric
+	(*SendMetricsRequest)(nil),       // 18: microwatcher.v1.SendMetricsRequest
+	(*SendMetricsResponse)(nil),      // 19: microwatcher.v1.SendMetricsResponse
+	(*LogEntry)(nil),                 // 20: microwatcher.v1.LogEntry
+	(*SendLogsRequest)(nil),          // 21: microwatcher.v1.SendLogsRequest
+	(*SendLogsResponse)(nil),         // 22: microwatcher.v1.SendLogsResponse
+	(*ProbeResult)(nil),              // 23: microwatcher.v1.ProbeResult
+	(*SendProbeResultsRequest)(nil),  // 24: microwatcher.v1.SendProbeResultsRequest
+	(*SendProbeResultsResponse)(nil), // 25: microwatcher.v1.SendProbeResultsResponse
+	(*InventoryInterface)(nil),       // 26: microwatcher.v1.InventoryInterface
+	(*DeviceInventory)(nil),          // 27: microwatcher.v1.DeviceInventory
+	(*HealthCheckRequest)(nil),       // 28: microwatcher.v1.HealthCheckRequest
+	nil,                              // 29: microwatcher.v1.CustomMetric.LabelsEntry
+	nil,                              // 30: microwatcher.v1.LogEntry.FieldsEntry
+	(*timestamppb.Timestamp)(nil),    // 31: google.protobuf.Timestamp
 }
 var file_microwatcher_v1_telemetry_service_proto_depIdxs = []int32{
-	9, // 0: microwatcher.v1.Telemetry.timestamp:type_name -> google.protobuf.Timestamp
-	4, // 1: microwatcher.v1.Telemetry.disks:type_name -> microwatcher.v1.TelemetryDisk
-	3, // 2: microwatcher.v1.Telemetry.networks:type_name -> microwatcher.v1.TelemetryNetwork
-	5, // 3: microwatcher.v1.SendTelemetryRequest.telemetries:type_name -> microwatcher.v1.Telemetry
-	9, // 4: microwatcher.v1.HealthCheckRequest.timestamp:type_name -> google.protobuf.Timestamp
-	6, // 5: microwatcher.v1.TelemetryService.SendTelemetry:input_type -> microwatcher.v1.SendTelemetryRequest
-	8, // 6: microwatcher.v1.TelemetryService.HealthCheck:input_type -> microwatcher.v1.HealthCheckRequest
-	1, // 7: microwatcher.v1.TelemetryService.Ping:input_type -> microwatcher.v1.PingRequest
-	7, // 8: microwatcher.v1.TelemetryService.SendTelemetry:output_type -> microwatcher.v1.SendTelemetryResponse
-	0, // 9: microwatcher.v1.TelemetryService.HealthCheck:output_type -> microwatcher.v1.Empty
-	2, // 10: microwatcher.v1.TelemetryService.Ping:output_type -> microwatcher.v1.PingResponse
-	8, // [8:11] is the sub-list for method output_type
-	5, // [5:8] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	31, // 0: microwatcher.v1.TelemetryCertificate.not_before:type_name -> google.protobuf.Timestamp
+	31, // 1: microwatcher.v1.TelemetryCertificate.not_after:type_name -> google.protobuf.Timestamp
+	31, // 2: microwatcher.v1.Telemetry.timestamp:type_name -> google.protobuf.Timestamp
+	4,  // 3: microwatcher.v1.Telemetry.disks:type_name -> microwatcher.v1.TelemetryDisk
+	3,  // 4: microwatcher.v1.Telemetry.networks:type_name -> microwatcher.v1.TelemetryNetwork
+	6,  // 5: microwatcher.v1.Telemetry.cpus:type_name -> microwatcher.v1.TelemetryCPU
+	5,  // 6: microwatcher.v1.Telemetry.disk_io:type_name -> microwatcher.v1.TelemetryDiskIO
+	13, // 7: microwatcher.v1.Telemetry.system:type_name -> microwatcher.v1.TelemetrySystem
+	7,  // 8: microwatcher.v1.Telemetry.processes:type_name -> microwatcher.v1.TelemetryProcess
+	8,  // 9: microwatcher.v1.Telemetry.sensors:type_name -> microwatcher.v1.TelemetrySensor
+	9,  // 10: microwatcher.v1.Telemetry.cgroups:type_name -> microwatcher.v1.TelemetryCgroup
+	12, // 11: microwatcher.v1.Telemetry.collector_errors:type_name -> microwatcher.v1.TelemetryCollectorError
+	10, // 12: microwatcher.v1.Telemetry.units:type_name -> microwatcher.v1.TelemetryUnit
+	11, // 13: microwatcher.v1.Telemetry.certificates:type_name -> microwatcher.v1.TelemetryCertificate
+	14, // 14: microwatcher.v1.SendTelemetryRequest.telemetries:type_name -> microwatcher.v1.Telemetry
+	31, // 15: microwatcher.v1.CustomMetric.timestamp:type_name -> google.protobuf.Timestamp
+	29, // 16: microwatcher.v1.CustomMetric.labels:type_name -> microwatcher.v1.CustomMetric.LabelsEntry
+	17, // 17: microwatcher.v1.SendMetricsRequest.metrics:type_name -> microwatcher.v1.CustomMetric
+	31, // 18: microwatcher.v1.LogEntry.timestamp:type_name -> google.protobuf.Timestamp
+	30, // 19: microwatcher.v1.LogEntry.fields:type_name -> microwatcher.v1.LogEntry.FieldsEntry
+	20, // 20: microwatcher.v1.SendLogsRequest.entries:type_name -> microwatcher.v1.LogEntry
+	31, // 21: microwatcher.v1.ProbeResult.timestamp:type_name -> google.protobuf.Timestamp
+	31, // 22: microwatcher.v1.ProbeResult.cert_not_after:type_name -> google.protobuf.Timestamp
+	23, // 23: microwatcher.v1.SendProbeResultsRequest.results:type_name -> microwatcher.v1.ProbeResult
+	31, // 24: microwatcher.v1.DeviceInventory.boot_time:type_name -> google.protobuf.Timestamp
+	26, // 25: microwatcher.v1.DeviceInventory.interfaces:type_name -> microwatcher.v1.InventoryInterface
+	31, // 26: microwatcher.v1.HealthCheckRequest.timestamp:type_name -> google.protobuf.Timestamp
+	27, // 27: microwatcher.v1.HealthCheckRequest.inventory:type_name -> microwatcher.v1.DeviceInventory
+	15, // 28: microwatcher.v1.TelemetryService.SendTelemetry:input_type -> microwatcher.v1.SendTelemetryRequest
+	18, // 29: microwatcher.v1.TelemetryService.SendMetrics:input_type -> microwatcher.v1.SendMetricsRequest
+	21, // 30: microwatcher.v1.TelemetryService.SendLogs:input_type -> microwatcher.v1.SendLogsRequest
+	24, // 31: microwatcher.v1.TelemetryService.SendProbeResults:input_type -> microwatcher.v1.SendProbeResultsRequest
+	28, // 32: microwatcher.v1.TelemetryService.HealthCheck:input_type -> microwatcher.v1.HealthCheckRequest
+	1,  // 33: microwatcher.v1.TelemetryService.Ping:input_type -> microwatcher.v1.PingRequest
+	16, // 34: microwatcher.v1.TelemetryService.SendTelemetry:output_type -> microwatcher.v1.SendTelemetryResponse
+	19, // 35: microwatcher.v1.TelemetryService.SendMetrics:output_type -> microwatcher.v1.SendMetricsResponse
+	22, // 36: microwatcher.v1.TelemetryService.SendLogs:output_type -> microwatcher.v1.SendLogsResponse
+	25, // 37: microwatcher.v1.TelemetryService.SendProbeResults:output_type -> microwatcher.v1.SendProbeResultsResponse
+	0,  // 38: microwatcher.v1.TelemetryService.HealthCheck:output_type -> microwatcher.v1.Empty
+	2,  // 39: microwatcher.v1.TelemetryService.Ping:output_type -> microwatcher.v1.PingResponse
+	34, // [34:40] is the sub-list for method output_type
+	28, // [28:34] is the sub-list for method input_type
+	28, // [28:28] is the sub-list for extension type_name
+	28, // [28:28] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
 }
 
 func init() { file_microwatcher_v1_telemetry_service_proto_init() }
@@ -633,7 +2867,7 @@ func file_microwatcher_v1_telemetry_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_microwatcher_v1_telemetry_service_proto_rawDesc), len(file_microwatcher_v1_telemetry_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
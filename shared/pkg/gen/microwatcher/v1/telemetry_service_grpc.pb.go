@@ -19,9 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TelemetryService_SendTelemetry_FullMethodName = "/microwatcher.v1.TelemetryService/SendTelemetry"
-	TelemetryService_HealthCheck_FullMethodName   = "/microwatcher.v1.TelemetryService/HealthCheck"
-	TelemetryService_Ping_FullMethodName          = "/microwatcher.v1.TelemetryService/Ping"
+	TelemetryService_SendTelemetry_FullMethodName    = "/microwatcher.v1.TelemetryService/SendTelemetry"
+	TelemetryService_SendMetrics_FullMethodName      = "/microwatcher.v1.TelemetryService/SendMetrics"
+	TelemetryService_SendLogs_FullMethodName         = "/microwatcher.v1.TelemetryService/SendLogs"
+	TelemetryService_SendProbeResults_FullMethodName = "/microwatcher.v1.TelemetryService/SendProbeResults"
+	TelemetryService_HealthCheck_FullMethodName      = "/microwatcher.v1.TelemetryService/HealthCheck"
+	TelemetryService_Ping_FullMethodName             = "/microwatcher.v1.TelemetryService/Ping"
 )
 
 // TelemetryServiceClient is the client API for TelemetryService service.
@@ -29,7 +32,11 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TelemetryServiceClient interface {
 	SendTelemetry(ctx context.Context, in *SendTelemetryRequest, opts ...grpc.CallOption) (*SendTelemetryResponse, error)
-	// i don't care about the response
+	SendMetrics(ctx context.Context, in *SendMetricsRequest, opts ...grpc.CallOption) (*SendMetricsResponse, error)
+	SendLogs(ctx context.Context, in *SendLogsRequest, opts ...grpc.CallOption) (*SendLogsResponse, error)
+	SendProbeResults(ctx context.Context, in *SendProbeResultsRequest, opts ...grpc.CallOption) (*SendProbeResultsResponse, error)
+	// i don't care about the response, it only fails when the inventory
+	// wasn't stored
 	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*Empty, error)
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
 }
@@ -52,6 +59,36 @@ func (c *telemetryServiceClient) SendTelemetry(ctx context.Context, in *SendTele
 	return out, nil
 }
 
+func (c *telemetryServiceClient) SendMetrics(ctx context.Context, in *SendMetricsRequest, opts ...grpc.CallOption) (*SendMetricsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendMetricsResponse)
+	err := c.cc.Invoke(ctx, TelemetryService_SendMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telemetryServiceClient) SendLogs(ctx context.Context, in *SendLogsRequest, opts ...grpc.CallOption) (*SendLogsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendLogsResponse)
+	err := c.cc.Invoke(ctx, TelemetryService_SendLogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telemetryServiceClient) SendProbeResults(ctx context.Context, in *SendProbeResultsRequest, opts ...grpc.CallOption) (*SendProbeResultsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendProbeResultsResponse)
+	err := c.cc.Invoke(ctx, TelemetryService_SendProbeResults_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *telemetryServiceClient) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Empty)
@@ -77,7 +114,11 @@ func (c *telemetryServiceClient) Ping(ctx context.Context, in *PingRequest, opts
 // for forward compatibility.
 type TelemetryServiceServer interface {
 	SendTelemetry(context.Context, *SendTelemetryRequest) (*SendTelemetryResponse, error)
-	// i don't care about the response
+	SendMetrics(context.Context, *SendMetricsRequest) (*SendMetricsResponse, error)
+	SendLogs(context.Context, *SendLogsRequest) (*SendLogsResponse, error)
+	SendProbeResults(context.Context, *SendProbeResultsRequest) (*SendProbeResultsResponse, error)
+	// i don't care about the response, it only fails when the inventory
+	// wasn't stored
 	HealthCheck(context.Context, *HealthCheckRequest) (*Empty, error)
 	Ping(context.Context, *PingRequest) (*PingResponse, error)
 	mustEmbedUnimplementedTelemetryServiceServer()
@@ -93,6 +134,15 @@ type UnimplementedTelemetryServiceServer struct{}
 func (UnimplementedTelemetryServiceServer) SendTelemetry(context.Context, *SendTelemetryRequest) (*SendTelemetryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendTelemetry not implemented")
 }
+func (UnimplementedTelemetryServiceServer) SendMetrics(context.Context, *SendMetricsRequest) (*SendMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendMetrics not implemented")
+}
+func (UnimplementedTelemetryServiceServer) SendLogs(context.Context, *SendLogsRequest) (*SendLogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendLogs not implemented")
+}
+func (UnimplementedTelemetryServiceServer) SendProbeResults(context.Context, *SendProbeResultsRequest) (*SendProbeResultsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendProbeResults not implemented")
+}
 func (UnimplementedTelemetryServiceServer) HealthCheck(context.Context, *HealthCheckRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
 }
@@ -138,6 +188,60 @@ func _TelemetryService_SendTelemetry_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TelemetryService_SendMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelemetryServiceServer).SendMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelemetryService_SendMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelemetryServiceServer).SendMetrics(ctx, req.(*SendMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelemetryService_SendLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelemetryServiceServer).SendLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelemetryService_SendLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelemetryServiceServer).SendLogs(ctx, req.(*SendLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelemetryService_SendProbeResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendProbeResultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelemetryServiceServer).SendProbeResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelemetryService_SendProbeResults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelemetryServiceServer).SendProbeResults(ctx, req.(*SendProbeResultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TelemetryService_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(HealthCheckRequest)
 	if err := dec(in); err != nil {
@@ -185,6 +289,18 @@ var TelemetryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendTelemetry",
 			Handler:    _TelemetryService_SendTelemetry_Handler,
 		},
+		{
+			MethodName: "SendMetrics",
+			Handler:    _TelemetryService_SendMetrics_Handler,
+		},
+		{
+			MethodName: "SendLogs",
+			Handler:    _TelemetryService_SendLogs_Handler,
+		},
+		{
+			MethodName: "SendProbeResults",
+			Handler:    _TelemetryService_SendProbeResults_Handler,
+		},
 		{
 			MethodName: "HealthCheck",
 			Handler:    _TelemetryService_HealthCheck_Handler,
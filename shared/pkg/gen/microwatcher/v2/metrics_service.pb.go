@@ -0,0 +1,523 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: microwatcher/v2/metrics_service.proto
+
+package microwatcherv2
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// === METRICS ===
+type MetricType int32
+
+const (
+	MetricType_METRIC_TYPE_UNSPECIFIED MetricType = 0
+	MetricType_METRIC_TYPE_GAUGE       MetricType = 1
+	// monotonic, resets when the producer restarts
+	MetricType_METRIC_TYPE_COUNTER   MetricType = 2
+	MetricType_METRIC_TYPE_HISTOGRAM MetricType = 3
+)
+
+// Enum value maps for MetricType.
+var (
+	MetricType_name = map[int32]string{
+		0: "METRIC_TYPE_UNSPECIFIED",
+		1: "METRIC_TYPE_GAUGE",
+		2: "METRIC_TYPE_COUNTER",
+		3: "METRIC_TYPE_HISTOGRAM",
+	}
+	MetricType_value = map[string]int32{
+		"METRIC_TYPE_UNSPECIFIED": 0,
+		"METRIC_TYPE_GAUGE":       1,
+		"METRIC_TYPE_COUNTER":     2,
+		"METRIC_TYPE_HISTOGRAM":   3,
+	}
+)
+
+func (x MetricType) Enum() *MetricType {
+	p := new(MetricType)
+	*p = x
+	return p
+}
+
+func (x MetricType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MetricType) Descriptor() protoreflect.EnumDescriptor {
+	return file_microwatcher_v2_metrics_service_proto_enumTypes[0].Descriptor()
+}
+
+func (MetricType) Type() protoreflect.EnumType {
+	return &file_microwatcher_v2_metrics_service_proto_enumTypes[0]
+}
+
+func (x MetricType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MetricType.Descriptor instead.
+func (MetricType) EnumDescriptor() ([]byte, []int) {
+	return file_microwatcher_v2_metrics_service_proto_rawDescGZIP(), []int{0}
+}
+
+type HistogramBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// inclusive, the last bucket is +Inf
+	UpperBound float64 `protobuf:"fixed64,1,opt,name=upper_bound,json=upperBound,proto3" json:"upper_bound,omitempty"`
+	// cumulative like in Prometheus, it counts every lower bucket as well
+	Count         uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistogramBucket) Reset() {
+	*x = HistogramBucket{}
+	mi := &file_microwatcher_v2_metrics_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistogramBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistogramBucket) ProtoMessage() {}
+
+func (x *HistogramBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v2_metrics_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistogramBucket.ProtoReflect.Descriptor instead.
+func (*HistogramBucket) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v2_metrics_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HistogramBucket) GetUpperBound() float64 {
+	if x != nil {
+		return x.UpperBound
+	}
+	return 0
+}
+
+func (x *HistogramBucket) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type Histogram struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buckets       []*HistogramBucket     `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	Sum           float64                `protobuf:"fixed64,2,opt,name=sum,proto3" json:"sum,omitempty"`
+	Count         uint64                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Histogram) Reset() {
+	*x = Histogram{}
+	mi := &file_microwatcher_v2_metrics_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Histogram) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Histogram) ProtoMessage() {}
+
+func (x *Histogram) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v2_metrics_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Histogram.ProtoReflect.Descriptor instead.
+func (*Histogram) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v2_metrics_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Histogram) GetBuckets() []*HistogramBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *Histogram) GetSum() float64 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *Histogram) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// a generic labeled sample, new measurements don't need a protocol change
+type Metric struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type      MetricType             `protobuf:"varint,3,opt,name=type,proto3,enum=microwatcher.v2.MetricType" json:"type,omitempty"`
+	// e.g. "bytes" or "seconds", empty when unknown
+	Unit   string            `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+	Labels map[string]string `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// gauges and counters set value, histograms set histogram
+	//
+	// Types that are valid to be assigned to Data:
+	//
+	//	*Metric_Value
+	//	*Metric_Histogram
+	Data          isMetric_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Metric) Reset() {
+	*x = Metric{}
+	mi := &file_microwatcher_v2_metrics_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metric) ProtoMessage() {}
+
+func (x *Metric) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v2_metrics_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metric.ProtoReflect.Descriptor instead.
+func (*Metric) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v2_metrics_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Metric) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Metric) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Metric) GetType() MetricType {
+	if x != nil {
+		return x.Type
+	}
+	return MetricType_METRIC_TYPE_UNSPECIFIED
+}
+
+func (x *Metric) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Metric) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Metric) GetData() isMetric_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Metric) GetValue() float64 {
+	if x != nil {
+		if x, ok := x.Data.(*Metric_Value); ok {
+			return x.Value
+		}
+	}
+	return 0
+}
+
+func (x *Metric) GetHistogram() *Histogram {
+	if x != nil {
+		if x, ok := x.Data.(*Metric_Histogram); ok {
+			return x.Histogram
+		}
+	}
+	return nil
+}
+
+type isMetric_Data interface {
+	isMetric_Data()
+}
+
+type Metric_Value struct {
+	Value float64 `protobuf:"fixed64,6,opt,name=value,proto3,oneof"`
+}
+
+type Metric_Histogram struct {
+	Histogram *Histogram `protobuf:"bytes,7,opt,name=histogram,proto3,oneof"`
+}
+
+func (*Metric_Value) isMetric_Data() {}
+
+func (*Metric_Histogram) isMetric_Data() {}
+
+type SendMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Metrics       []*Metric              `protobuf:"bytes,2,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendMetricsRequest) Reset() {
+	*x = SendMetricsRequest{}
+	mi := &file_microwatcher_v2_metrics_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMetricsRequest) ProtoMessage() {}
+
+func (x *SendMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v2_metrics_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMetricsRequest.ProtoReflect.Descriptor instead.
+func (*SendMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v2_metrics_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SendMetricsRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *SendMetricsRequest) GetMetrics() []*Metric {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+type SendMetricsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendMetricsResponse) Reset() {
+	*x = SendMetricsResponse{}
+	mi := &file_microwatcher_v2_metrics_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMetricsResponse) ProtoMessage() {}
+
+func (x *SendMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_microwatcher_v2_metrics_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMetricsResponse.ProtoReflect.Descriptor instead.
+func (*SendMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_microwatcher_v2_metrics_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SendMetricsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+var File_microwatcher_v2_metrics_service_proto protoreflect.FileDescriptor
+
+const file_microwatcher_v2_metrics_service_proto_rawDesc = "" +
+	"\n" +
+	"%microwatcher/v2/metrics_service.proto\x12\x0fmicrowatcher.v2\x1a\x1fgoogle/protobuf/timestamp.proto\"H\n" +
+	"\x0fHistogramBucket\x12\x1f\n" +
+	"\vupper_bound\x18\x01 \x01(\x01R\n" +
+	"upperBound\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\"o\n" +
+	"\tHistogram\x12:\n" +
+	"\abuckets\x18\x01 \x03(\v2 .microwatcher.v2.HistogramBucketR\abuckets\x12\x10\n" +
+	"\x03sum\x18\x02 \x01(\x01R\x03sum\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x04R\x05count\"\xef\x02\n" +
+	"\x06Metric\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12/\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x1b.microwatcher.v2.MetricTypeR\x04type\x12\x12\n" +
+	"\x04unit\x18\x04 \x01(\tR\x04unit\x12;\n" +
+	"\x06labels\x18\x05 \x03(\v2#.microwatcher.v2.Metric.LabelsEntryR\x06labels\x12\x16\n" +
+	"\x05value\x18\x06 \x01(\x01H\x00R\x05value\x12:\n" +
+	"\thistogram\x18\a \x01(\v2\x1a.microwatcher.v2.HistogramH\x00R\thistogram\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x06\n" +
+	"\x04data\"g\n" +
+	"\x12SendMetricsRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x121\n" +
+	"\ametrics\x18\x02 \x03(\v2\x17.microwatcher.v2.MetricR\ametrics\"/\n" +
+	"\x13SendMetricsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess*t\n" +
+	"\n" +
+	"MetricType\x12\x1b\n" +
+	"\x17METRIC_TYPE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11METRIC_TYPE_GAUGE\x10\x01\x12\x17\n" +
+	"\x13METRIC_TYPE_COUNTER\x10\x02\x12\x19\n" +
+	"\x15METRIC_TYPE_HISTOGRAM\x10\x032l\n" +
+	"\x0eMetricsService\x12Z\n" +
+	"\vSendMetrics\x12#.microwatcher.v2.SendMetricsRequest\x1a$.microwatcher.v2.SendMetricsResponse\"\x00BCZAgithub.com/microwatcher/shared/gen/microwatcher/v2;microwatcherv2b\x06proto3"
+
+var (
+	file_microwatcher_v2_metrics_service_proto_rawDescOnce sync.Once
+	file_microwatcher_v2_metrics_service_proto_rawDescData []byte
+)
+
+func file_microwatcher_v2_metrics_service_proto_rawDescGZIP() []byte {
+	file_microwatcher_v2_metrics_service_proto_rawDescOnce.Do(func() {
+		file_microwatcher_v2_metrics_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_microwatcher_v2_metrics_service_proto_rawDesc), len(file_microwatcher_v2_metrics_service_proto_rawDesc)))
+	})
+	return file_microwatcher_v2_metrics_service_proto_rawDescData
+}
+
+var file_microwatcher_v2_metrics_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_microwatcher_v2_metrics_service_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_microwatcher_v2_metrics_service_proto_goTypes = []any{
+	(MetricType)(0),               // 0: microwatcher.v2.MetricType
+	(*HistogramBucket)(nil),       // 1: microwatcher.v2.HistogramBucket
+	(*Histogram)(nil),             // 2: microwatcher.v2.Histogram
+	(*Metric)(nil),                // 3: microwatcher.v2.Metric
+	(*SendMetricsRequest)(nil),    // 4: microwatcher.v2.SendMetricsRequest
+	(*SendMetricsResponse)(nil),   // 5: microwatcher.v2.SendMetricsResponse
+	nil,                           // 6: microwatcher.v2.Metric.LabelsEntry
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_microwatcher_v2_metrics_service_proto_depIdxs = []int32{
+	1, // 0: microwatcher.v2.Histogram.buckets:type_name -> microwatcher.v2.HistogramBucket
+	7, // 1: microwatcher.v2.Metric.timestamp:type_name -> google.protobuf.Timestamp
+	0, // 2: microwatcher.v2.Metric.type:type_name -> microwatcher.v2.MetricType
+	6, // 3: microwatcher.v2.Metric.labels:type_name -> microwatcher.v2.Metric.LabelsEntry
+	2, // 4: microwatcher.v2.Metric.histogram:type_name -> microwatcher.v2.Histogram
+	3, // 5: microwatcher.v2.SendMetricsRequest.metrics:type_name -> microwatcher.v2.Metric
+	4, // 6: microwatcher.v2.MetricsService.SendMetrics:input_type -> microwatcher.v2.SendMetricsRequest
+	5, // 7: microwatcher.v2.MetricsService.SendMetrics:output_type -> microwatcher.v2.SendMetricsResponse
+	7, // [7:8] is the sub-list for method output_type
+	6, // [6:7] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_microwatcher_v2_metrics_service_proto_init() }
+func file_microwatcher_v2_metrics_service_proto_init() {
+	if File_microwatcher_v2_metrics_service_proto != nil {
+		return
+	}
+	file_microwatcher_v2_metrics_service_proto_msgTypes[2].OneofWrappers = []any{
+		(*Metric_Value)(nil),
+		(*Metric_Histogram)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_microwatcher_v2_metrics_service_proto_rawDesc), len(file_microwatcher_v2_metrics_service_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_microwatcher_v2_metrics_service_proto_goTypes,
+		DependencyIndexes: file_microwatcher_v2_metrics_service_proto_depIdxs,
+		EnumInfos:         file_microwatcher_v2_metrics_service_proto_enumTypes,
+		MessageInfos:      file_microwatcher_v2_metrics_service_proto_msgTypes,
+	}.Build()
+	File_microwatcher_v2_metrics_service_proto = out.File
+	file_microwatcher_v2_metrics_service_proto_goTypes = nil
+	file_microwatcher_v2_metrics_service_proto_depIdxs = nil
+}
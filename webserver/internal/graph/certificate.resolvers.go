@@ -0,0 +1,69 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/microwatcher/shared/pkg/clickhouse"
+	"github.com/microwatcher/shared/pkg/iter"
+	"github.com/microwatcher/shared/pkg/otlp"
+	"github.com/microwatcher/webserver/internal/graph/model"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ExpiringCertificates is the resolver for the expiringCertificates field.
+func (r *queryResolver) ExpiringCertificates(ctx context.Context, withinDays int) (model.CertificateQueryResult, error) {
+	spanCtx, span := otlp.WebServerTracer.Start(
+		ctx,
+		"QueryResolver.ExpiringCertificates",
+		trace.WithAttributes(
+			attribute.Int("withinDays", withinDays),
+		),
+	)
+	defer span.End()
+
+	now := time.Now()
+	chCertificates, err := r.ChSource.ListExpiringCertificates(spanCtx, now.AddDate(0, 0, withinDays))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to list expiring certificates")
+
+		return model.GenericError{
+			Message: err.Error(),
+		}, nil
+	}
+
+	span.SetStatus(codes.Ok, "listed expiring certificates")
+
+	return model.CertificateList{
+		Certificates: iter.Map(chCertificates, func(chCertificate *clickhouse.ClickhouseCertificate) *model.Certificate {
+			return &model.Certificate{
+				DeviceID:    chCertificate.DeviceID,
+				Identifier:  chCertificate.Identifier,
+				Source:      chCertificate.Source,
+				Location:    chCertificate.Location,
+				Fingerprint: chCertificate.Fingerprint,
+				Subject:     chCertificate.Subject,
+				Issuer:      chCertificate.Issuer,
+				Sans:        chCertificate.SANs,
+				NotBefore:   chCertificate.NotBefore,
+				NotAfter:    chCertificate.NotAfter,
+				// from now rather than from the scan, which can be an hour old
+				DaysRemaining: int(math.Floor(chCertificate.NotAfter.Sub(now).Hours() / 24)),
+				LastSeen:      chCertificate.Timestamp,
+			}
+		}),
+	}, nil
+}
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type queryResolver struct{ *Resolver }
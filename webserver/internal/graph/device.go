@@ -0,0 +1,37 @@
+package graph
+
+import (
+	"github.com/microwatcher/shared/pkg/clickhouse"
+	"github.com/microwatcher/webserver/internal/graph/model"
+)
+
+func toDeviceInventory(chInventory *clickhouse.ClickhouseDeviceInventory) *model.DeviceInventory {
+	interfaces := make([]*model.DeviceInterface, len(chInventory.InterfaceNames))
+	for idx, name := range chInventory.InterfaceNames {
+		interfaces[idx] = &model.DeviceInterface{
+			Name:      name,
+			Mac:       chInventory.InterfaceMACs[idx],
+			Addresses: chInventory.InterfaceAddresses[idx],
+		}
+	}
+
+	return &model.DeviceInventory{
+		ReportedAt:           chInventory.Timestamp,
+		Hostname:             chInventory.Hostname,
+		Os:                   chInventory.OS,
+		Platform:             chInventory.Platform,
+		PlatformFamily:       chInventory.PlatformFamily,
+		PlatformVersion:      chInventory.PlatformVersion,
+		KernelVersion:        chInventory.KernelVersion,
+		Arch:                 chInventory.Arch,
+		CPUModel:             chInventory.CPUModel,
+		CPUCores:             int(chInventory.CPUCores),
+		CPUThreads:           int(chInventory.CPUThreads),
+		TotalMemory:          float64(chInventory.TotalMemory),
+		BootTime:             chInventory.BootTime,
+		Interfaces:           interfaces,
+		AgentVersion:         chInventory.AgentVersion,
+		VirtualizationSystem: chInventory.VirtualizationSystem,
+		VirtualizationRole:   chInventory.VirtualizationRole,
+	}
+}
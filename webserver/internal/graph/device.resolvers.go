@@ -87,14 +87,31 @@ func (r *queryResolver) Devices(ctx context.Context) (model.DeviceQueryResult, e
 		}, nil
 	}
 
+	// one query for all devices instead of one per device
+	chInventories, err := r.ChSource.ListDeviceInventories(spanCtx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to list device inventories")
+
+		return model.GenericError{
+			Message: err.Error(),
+		}, nil
+	}
+
+	inventories := make(map[uuid.UUID]*model.DeviceInventory, len(chInventories))
+	for _, chInventory := range chInventories {
+		inventories[chInventory.DeviceID] = toDeviceInventory(chInventory)
+	}
+
 	span.SetStatus(codes.Ok, "listed devices")
 
 	return model.DeviceList{
 		Devices: iter.Map(chDevices, func(chDevice *clickhouse.ClickhouseDevice) *model.Device {
 			return &model.Device{
-				ID:     uuid.MustParse(chDevice.ID.String()),
-				Label:  chDevice.Label,
-				Secret: chDevice.Secret,
+				ID:        uuid.MustParse(chDevice.ID.String()),
+				Label:     chDevice.Label,
+				Secret:    chDevice.Secret,
+				Inventory: inventories[chDevice.ID],
 			}
 		}),
 	}, nil
@@ -103,8 +120,4 @@ func (r *queryResolver) Devices(ctx context.Context) (model.DeviceQueryResult, e
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
-// Query returns QueryResolver implementation.
-func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
-
 type mutationResolver struct{ *Resolver }
-type queryResolver struct{ *Resolver }
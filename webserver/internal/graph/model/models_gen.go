@@ -3,9 +3,15 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
+type CertificateQueryResult interface {
+	IsCertificateQueryResult()
+}
+
 type DeviceMutationResult interface {
 	IsDeviceMutationResult()
 }
@@ -35,18 +41,66 @@ type BooleanResult struct {
 
 func (BooleanResult) IsResetDeviceSecretResult() {}
 
+type Certificate struct {
+	DeviceID      uuid.UUID `json:"deviceID"`
+	Identifier    string    `json:"identifier"`
+	Source        string    `json:"source"`
+	Location      string    `json:"location"`
+	Fingerprint   string    `json:"fingerprint"`
+	Subject       string    `json:"subject"`
+	Issuer        string    `json:"issuer"`
+	Sans          []string  `json:"sans"`
+	NotBefore     time.Time `json:"notBefore"`
+	NotAfter      time.Time `json:"notAfter"`
+	DaysRemaining int       `json:"daysRemaining"`
+	LastSeen      time.Time `json:"lastSeen"`
+}
+
+type CertificateList struct {
+	Certificates []*Certificate `json:"certificates"`
+}
+
+func (CertificateList) IsCertificateQueryResult() {}
+
 type CreateDevice struct {
 	Label string `json:"label"`
 }
 
 type Device struct {
-	ID     uuid.UUID `json:"id"`
-	Label  string    `json:"label"`
-	Secret string    `json:"secret"`
+	ID        uuid.UUID        `json:"id"`
+	Label     string           `json:"label"`
+	Secret    string           `json:"secret"`
+	Inventory *DeviceInventory `json:"inventory,omitempty"`
 }
 
 func (Device) IsDeviceMutationResult() {}
 
+type DeviceInterface struct {
+	Name      string   `json:"name"`
+	Mac       string   `json:"mac"`
+	Addresses []string `json:"addresses"`
+}
+
+type DeviceInventory struct {
+	ReportedAt           time.Time          `json:"reportedAt"`
+	Hostname             string             `json:"hostname"`
+	Os                   string             `json:"os"`
+	Platform             string             `json:"platform"`
+	PlatformFamily       string             `json:"platformFamily"`
+	PlatformVersion      string             `json:"platformVersion"`
+	KernelVersion        string             `json:"kernelVersion"`
+	Arch                 string             `json:"arch"`
+	CPUModel             string             `json:"cpuModel"`
+	CPUCores             int                `json:"cpuCores"`
+	CPUThreads           int                `json:"cpuThreads"`
+	TotalMemory          float64            `json:"totalMemory"`
+	BootTime             time.Time          `json:"bootTime"`
+	Interfaces           []*DeviceInterface `json:"interfaces"`
+	AgentVersion         string             `json:"agentVersion"`
+	VirtualizationSystem string             `json:"virtualizationSystem"`
+	VirtualizationRole   string             `json:"virtualizationRole"`
+}
+
 type DeviceList struct {
 	Devices []*Device `json:"devices"`
 }
@@ -57,6 +111,8 @@ type GenericError struct {
 	Message string `json:"message"`
 }
 
+func (GenericError) IsCertificateQueryResult() {}
+
 func (GenericError) IsDeviceQueryResult() {}
 
 func (GenericError) IsDeviceMutationResult() {}